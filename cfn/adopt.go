@@ -0,0 +1,38 @@
+package cfn
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+)
+
+// shouldAdopt reports whether the resource adopts an existing Mackerel object instead of creating new one.
+// Adoption is considered only on creation.
+// When CloudFormation replaces the resource, the old object may have the same name,
+// but it must not be adopted because CloudFormation will delete it after the replacement.
+func shouldAdopt(event cfn.Event) (bool, error) {
+	if event.RequestType != cfn.RequestCreate {
+		return false, nil
+	}
+	in := dproxy.New(event.ResourceProperties)
+	return dproxy.Default(in.M("Adopt"), false).Bool()
+}
+
+// findByName returns the id of the object that has the name.
+// It returns an empty string if no object is found,
+// and returns an error if two or more objects are found because the object to adopt is ambiguous.
+func findByName[T any](kind, name string, items []T, key func(T) (name, id string)) (string, error) {
+	var found string
+	for _, item := range items {
+		n, id := key(item)
+		if n != name {
+			continue
+		}
+		if found != "" {
+			return "", fmt.Errorf("failed to adopt: two or more %ss named %q are found", kind, name)
+		}
+		found = id
+	}
+	return found, nil
+}
@@ -0,0 +1,394 @@
+package cfn
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestAdoptMonitor(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findMonitors: func(ctx context.Context) ([]mackerel.Monitor, error) {
+				return []mackerel.Monitor{
+					// same name, but different type
+					&mackerel.MonitorHostMetric{
+						ID:   "2cSZzK3XfmG",
+						Name: "foo-bar",
+					},
+					&mackerel.MonitorConnectivity{
+						ID:   "3yAYEDLXKL5",
+						Name: "foo-bar",
+					},
+				}, nil
+			},
+			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
+				t.Error("the monitor should be adopted, not created")
+				return nil, nil
+			},
+			updateMonitor: func(ctx context.Context, monitorID string, param mackerel.Monitor) (mackerel.Monitor, error) {
+				if monitorID != "3yAYEDLXKL5" {
+					t.Errorf("unexpected monitor id: want %s, got %s", "3yAYEDLXKL5", monitorID)
+				}
+				ret := *param.(*mackerel.MonitorConnectivity)
+				ret.ID = monitorID
+				return &ret, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Monitor",
+		LogicalResourceID: "Monitor",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":  "connectivity",
+			"Name":  "foo-bar",
+			"Adopt": "true",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:monitor:3yAYEDLXKL5" {
+		t.Errorf("unexpected monitor id: want %s, got %s", "mkr:test-org:monitor:3yAYEDLXKL5", id)
+	}
+}
+
+func TestAdoptMonitor_notFound(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findMonitors: func(ctx context.Context) ([]mackerel.Monitor, error) {
+				return []mackerel.Monitor{}, nil
+			},
+			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
+				ret := *param.(*mackerel.MonitorConnectivity)
+				ret.ID = "3yAYEDLXKL5"
+				return &ret, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Monitor",
+		LogicalResourceID: "Monitor",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":  "connectivity",
+			"Name":  "foo-bar",
+			"Adopt": true,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:monitor:3yAYEDLXKL5" {
+		t.Errorf("unexpected monitor id: want %s, got %s", "mkr:test-org:monitor:3yAYEDLXKL5", id)
+	}
+}
+
+func TestAdoptMonitor_replacement(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findMonitors: func(ctx context.Context) ([]mackerel.Monitor, error) {
+				t.Error("the old monitor must not be adopted on replacement")
+				return nil, nil
+			},
+			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
+				ret := *param.(*mackerel.MonitorExpression)
+				ret.ID = "2cSZzK3XfmG"
+				return &ret, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::Monitor",
+		LogicalResourceID:  "Monitor",
+		PhysicalResourceID: "mkr:test-org:monitor:3yAYEDLXKL5",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":       "expression",
+			"Name":       "foo-bar",
+			"Expression": "max(role(\"foo:bar\", \"loadavg5\"))",
+			"Operator":   ">",
+			"Adopt":      true,
+		},
+		OldResourceProperties: map[string]any{
+			"Type":  "connectivity",
+			"Name":  "foo-bar",
+			"Adopt": true,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:monitor:2cSZzK3XfmG" {
+		t.Errorf("unexpected monitor id: want %s, got %s", "mkr:test-org:monitor:2cSZzK3XfmG", id)
+	}
+}
+
+func TestAdoptDashboard(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findDashboards: func(ctx context.Context) ([]*mackerel.Dashboard, error) {
+				return []*mackerel.Dashboard{
+					{
+						ID:    "2cSZzK3XfmG",
+						Title: "other dashboard",
+					},
+					{
+						ID:    "3yAYEDLXKL5",
+						Title: "awesome dashboard",
+					},
+				}, nil
+			},
+			createDashboard: func(ctx context.Context, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
+				t.Error("the dashboard should be adopted, not created")
+				return nil, nil
+			},
+			updateDashboard: func(ctx context.Context, dashboardID string, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
+				if dashboardID != "3yAYEDLXKL5" {
+					t.Errorf("unexpected dashboard id: want %s, got %s", "3yAYEDLXKL5", dashboardID)
+				}
+				ret := *param
+				ret.ID = dashboardID
+				return &ret, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Dashboard",
+		LogicalResourceID: "Dashboard",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Title":   "awesome dashboard",
+			"UrlPath": "awesome-dashboard",
+			"Widgets": []any{},
+			"Adopt":   true,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:dashboard:3yAYEDLXKL5" {
+		t.Errorf("unexpected dashboard id: want %s, got %s", "mkr:test-org:dashboard:3yAYEDLXKL5", id)
+	}
+}
+
+func TestAdoptNotificationChannel(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findNotificationChannels: func(ctx context.Context) ([]mackerel.NotificationChannel, error) {
+				return []mackerel.NotificationChannel{
+					&mackerel.NotificationChannelWebHook{
+						ID:   "3yAYEDLXKL5",
+						Name: "webhook",
+					},
+				}, nil
+			},
+			createNotificationChannel: func(ctx context.Context, ch mackerel.NotificationChannel) (mackerel.NotificationChannel, error) {
+				t.Error("the notification channel should be adopted, not created")
+				return nil, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::NotificationChannel",
+		LogicalResourceID: "NotificationChannel",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":   "webhook",
+			"Name":   "webhook",
+			"Url":    "https://example.com/webhook",
+			"Events": []any{"alert"},
+			"Adopt":  true,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:notification-channel:3yAYEDLXKL5" {
+		t.Errorf("unexpected channel id: want %s, got %s", "mkr:test-org:notification-channel:3yAYEDLXKL5", id)
+	}
+}
+
+func TestAdoptNotificationGroup_ambiguous(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findNotificationGroups: func(ctx context.Context) ([]*mackerel.NotificationGroup, error) {
+				return []*mackerel.NotificationGroup{
+					{
+						ID:   "2cSZzK3XfmG",
+						Name: "notification group",
+					},
+					{
+						ID:   "3yAYEDLXKL5",
+						Name: "notification group",
+					},
+				}, nil
+			},
+			createNotificationGroup: func(ctx context.Context, group *mackerel.NotificationGroup) (*mackerel.NotificationGroup, error) {
+				t.Error("the notification group should not be created")
+				return nil, nil
+			},
+			updateNotificationGroup: func(ctx context.Context, groupID string, group *mackerel.NotificationGroup) (*mackerel.NotificationGroup, error) {
+				t.Error("the notification group should not be updated")
+				return nil, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::NotificationGroup",
+		LogicalResourceID: "NotificationGroup",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name":  "notification group",
+			"Adopt": true,
+		},
+	}
+	_, _, err := f.Handle(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "two or more notification groups") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAdoptDowntime(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findDowntimes: func(ctx context.Context) ([]*mackerel.Downtime, error) {
+				return []*mackerel.Downtime{
+					{
+						ID:   "3yAYEDLXKL5",
+						Name: "Maintenance #1",
+					},
+				}, nil
+			},
+			createDowntime: func(ctx context.Context, param *mackerel.Downtime) (*mackerel.Downtime, error) {
+				t.Error("the downtime should be adopted, not created")
+				return nil, nil
+			},
+			updateDowntime: func(ctx context.Context, downtimeID string, param *mackerel.Downtime) (*mackerel.Downtime, error) {
+				if downtimeID != "3yAYEDLXKL5" {
+					t.Errorf("unexpected downtime id: want %s, got %s", "3yAYEDLXKL5", downtimeID)
+				}
+				ret := *param
+				ret.ID = downtimeID
+				return &ret, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Downtime",
+		LogicalResourceID: "Downtime",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name":     "Maintenance #1",
+			"Start":    1563700000,
+			"Duration": 60,
+			"Adopt":    true,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:downtime:3yAYEDLXKL5" {
+		t.Errorf("unexpected downtime id: want %s, got %s", "mkr:test-org:downtime:3yAYEDLXKL5", id)
+	}
+}
+
+func TestAdoptDashboard_ambiguous(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findDashboards: func(ctx context.Context) ([]*mackerel.Dashboard, error) {
+				return []*mackerel.Dashboard{
+					{
+						ID:    "2cSZzK3XfmG",
+						Title: "dashboard",
+					},
+					{
+						ID:    "3yAYEDLXKL5",
+						Title: "dashboard",
+					},
+				}, nil
+			},
+			createDashboard: func(ctx context.Context, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
+				t.Error("the dashboard should not be created")
+				return nil, nil
+			},
+			updateDashboard: func(ctx context.Context, dashboardID string, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
+				t.Error("the dashboard should not be updated")
+				return nil, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Dashboard",
+		LogicalResourceID: "Dashboard",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Title":   "dashboard",
+			"UrlPath": "dashboard",
+			"Widgets": []any{},
+			"Adopt":   true,
+		},
+	}
+	_, _, err := f.Handle(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "two or more dashboards") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
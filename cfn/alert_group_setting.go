@@ -0,0 +1,146 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+type alertGroupSetting struct {
+	Function *Function
+	Event    cfn.Event
+}
+
+func (r *alertGroupSetting) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	param, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return
+	}
+	ret, err := c.CreateAlertGroupSetting(ctx, param)
+	if err != nil {
+		return
+	}
+
+	physicalResourceID, err = r.Function.buildAlertGroupSettingID(ctx, ret.ID)
+	return
+}
+
+func (r *alertGroupSetting) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	physicalResourceID = r.Event.PhysicalResourceID
+	param, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return
+	}
+	id, err := r.Function.parseAlertGroupSettingID(ctx, physicalResourceID)
+	if err != nil {
+		return
+	}
+	current, err := c.FindAlertGroupSetting(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = r.checkOwner(current); err != nil {
+		return
+	}
+	_, err = c.UpdateAlertGroupSetting(ctx, id, param)
+	return
+}
+
+// checkOwner returns an error if the alert group setting is owned by another stack.
+func (r *alertGroupSetting) checkOwner(setting *mackerel.AlertGroupSetting) error {
+	resource := fmt.Sprintf("the alert group setting %s", setting.ID)
+	return checkOwner(r.Event, resource, ownerFromMemo(setting.Memo))
+}
+
+func (r *alertGroupSetting) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.AlertGroupSetting, error) {
+	var param mackerel.AlertGroupSetting
+	var d dproxy.Drain
+	in, tracker := dproxy.Track(properties)
+
+	param.Name = d.String(in.M("Name"))
+	param.Memo = withOwner(d.String(dproxy.Default(in.M("Memo"), "")), r.Event)
+	param.NotificationInterval = d.Uint64(dproxy.Default(in.M("NotificationInterval"), 0))
+
+	// Service Scopes
+	if scopes, err := in.M("ServiceScopes").ProxySet().StringArray(); err == nil {
+		services := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			name, err := r.Function.parseServiceID(ctx, scope)
+			d.Put(err)
+			services = append(services, name)
+		}
+		param.ServiceScopes = services
+	} else if !dproxy.IsErrorCode(err, dproxy.ErrorCodeNotFound) {
+		d.Put(err)
+	}
+
+	// Role Scopes
+	if scopes, err := in.M("RoleScopes").ProxySet().StringArray(); err == nil {
+		roles := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			service, role, err := r.Function.parseRoleID(ctx, scope)
+			d.Put(err)
+			roles = append(roles, service+":"+role)
+		}
+		param.RoleScopes = roles
+	} else if !dproxy.IsErrorCode(err, dproxy.ErrorCodeNotFound) {
+		d.Put(err)
+	}
+
+	// Monitor Scopes
+	if scopes, err := in.M("MonitorScopes").ProxySet().StringArray(); err == nil {
+		monitors := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			monitor, err := r.Function.parseMonitorID(ctx, scope)
+			d.Put(err)
+			monitors = append(monitors, monitor)
+		}
+		param.MonitorScopes = monitors
+	} else if !dproxy.IsErrorCode(err, dproxy.ErrorCodeNotFound) {
+		d.Put(err)
+	}
+
+	checkUnknownProperties(&d, r.Event, tracker)
+	if err := d.CombineErrors(); err != nil {
+		return nil, err
+	}
+	return &param, nil
+}
+
+func (r *alertGroupSetting) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	physicalResourceID = r.Event.PhysicalResourceID
+	id, err := r.Function.parseAlertGroupSettingID(ctx, physicalResourceID)
+	if err != nil {
+		log.Printf("failed to parse %q as alert group setting id: %s", physicalResourceID, err)
+		err = nil // ignore it
+		return
+	}
+	var merr mackerel.Error
+	current, err := c.FindAlertGroupSetting(ctx, id)
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the alert group setting %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	if err = r.checkOwner(current); err != nil {
+		return
+	}
+	_, err = c.DeleteAlertGroupSetting(ctx, id)
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the alert group setting %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,211 @@
+package cfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestCreateAlertGroupSetting(t *testing.T) {
+	r := &alertGroupSetting{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createAlertGroupSetting: func(ctx context.Context, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error) {
+					want := &mackerel.AlertGroupSetting{
+						Name: "Alert Group #1",
+						Memo: "Memo #1\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+						ServiceScopes: []string{
+							"service1",
+						},
+						RoleScopes: []string{
+							"service2:role1",
+						},
+						MonitorScopes: []string{
+							"monitor0",
+						},
+						NotificationInterval: 60,
+					}
+					if diff := cmp.Diff(param, want); diff != "" {
+						t.Errorf("param differs: (-got +want)\n%s", diff)
+					}
+					ret := *param
+					ret.ID = "3yAYEDLXKL5"
+					return &ret, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			RequestID:         "",
+			ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:      "Custom:AlertGroupSetting",
+			LogicalResourceID: "AlertGroupSetting",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name": "Alert Group #1",
+				"Memo": "Memo #1",
+				"ServiceScopes": []any{
+					"mkr:test-org:service:service1",
+				},
+				"RoleScopes": []any{
+					"mkr:test-org:role:service2:role1",
+				},
+				"MonitorScopes": []any{
+					"mkr:test-org:monitor:monitor0",
+				},
+				"NotificationInterval": "60",
+			},
+		},
+	}
+	id, _, err := r.create(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:alert-group-setting:3yAYEDLXKL5" {
+		t.Errorf("unexpected alert group setting id: want %s, got %s", "mkr:test-org:alert-group-setting:3yAYEDLXKL5", id)
+	}
+}
+
+func TestCreateAlertGroupSetting_invalidScope(t *testing.T) {
+	r := &alertGroupSetting{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createAlertGroupSetting: func(ctx context.Context, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error) {
+					t.Error("the alert group setting should not be created")
+					return nil, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			RequestID:         "",
+			ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:      "Custom:AlertGroupSetting",
+			LogicalResourceID: "AlertGroupSetting",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name": "Alert Group #1",
+				"ServiceScopes": []any{
+					// it is a role, not a service.
+					"mkr:test-org:role:service2:role1",
+				},
+			},
+		},
+	}
+	if _, _, err := r.create(context.Background()); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestUpdateAlertGroupSetting(t *testing.T) {
+	r := &alertGroupSetting{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				findAlertGroupSetting: func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+					return &mackerel.AlertGroupSetting{
+						ID:   settingID,
+						Name: "Alert Group #1",
+						Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					}, nil
+				},
+				updateAlertGroupSetting: func(ctx context.Context, settingID string, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error) {
+					if settingID != "3yAYEDLXKL5" {
+						t.Errorf("unexpected alert group setting id: want %s, got %s", "3yAYEDLXKL5", settingID)
+					}
+					want := &mackerel.AlertGroupSetting{
+						Name: "Alert Group #1",
+						Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+						MonitorScopes: []string{
+							"monitor1",
+						},
+					}
+					if diff := cmp.Diff(param, want); diff != "" {
+						t.Errorf("param differs: (-got +want)\n%s", diff)
+					}
+					ret := *param
+					ret.ID = settingID
+					return &ret, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			RequestID:          "",
+			ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:       "Custom:AlertGroupSetting",
+			LogicalResourceID:  "AlertGroupSetting",
+			PhysicalResourceID: "mkr:test-org:alert-group-setting:3yAYEDLXKL5",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name": "Alert Group #1",
+				"MonitorScopes": []any{
+					"mkr:test-org:monitor:monitor1",
+				},
+			},
+			OldResourceProperties: map[string]any{
+				"Name": "Alert Group #1",
+				"MonitorScopes": []any{
+					"mkr:test-org:monitor:monitor0",
+				},
+			},
+		},
+	}
+	id, _, err := r.update(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:alert-group-setting:3yAYEDLXKL5" {
+		t.Errorf("unexpected alert group setting id: want %s, got %s", "mkr:test-org:alert-group-setting:3yAYEDLXKL5", id)
+	}
+}
+
+func TestDeleteAlertGroupSetting(t *testing.T) {
+	r := &alertGroupSetting{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				findAlertGroupSetting: func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+					return &mackerel.AlertGroupSetting{
+						ID:   settingID,
+						Name: "Alert Group #1",
+					}, nil
+				},
+				deleteAlertGroupSetting: func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+					if settingID != "3yAYEDLXKL5" {
+						t.Errorf("unexpected alert group setting id: want %s, got %s", "3yAYEDLXKL5", settingID)
+					}
+					return nil, mkrError{
+						statusCode: 404,
+						message:    "Alert group setting not found",
+					}
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestDelete,
+			RequestID:          "",
+			ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:       "Custom:AlertGroupSetting",
+			LogicalResourceID:  "AlertGroupSetting",
+			PhysicalResourceID: "mkr:test-org:alert-group-setting:3yAYEDLXKL5",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+	}
+	if _, _, err := r.delete(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,104 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// APIKeyResolver creates the api key providers for the ApiKeyParameter and ApiKeySecret properties,
+// which allow a stack to manage two or more Mackerel organizations.
+type APIKeyResolver interface {
+	// ParameterProvider returns a provider which gets the api key from the parameter.
+	ParameterProvider(name string) mackerel.APIKeyProvider
+
+	// SecretProvider returns a provider which gets the api key from the secret.
+	SecretProvider(secretID string) mackerel.APIKeyProvider
+}
+
+// forResource returns the function that handles the resource.
+// If the resource specifies its own api key, the function for the api key is returned from the pool,
+// so the client and the organization are cached per api key.
+// Otherwise, f itself is returned.
+func (f *Function) forResource(event cfn.Event) (*Function, error) {
+	var d dproxy.Drain
+	in := dproxy.New(event.ResourceProperties)
+	parameter := d.String(dproxy.Default(in.M("ApiKeyParameter"), ""))
+	secret := d.String(dproxy.Default(in.M("ApiKeySecret"), ""))
+	if err := d.CombineErrors(); err != nil {
+		return nil, err
+	}
+
+	var key string
+	switch {
+	case parameter == "" && secret == "":
+		return f, nil
+	case parameter != "" && secret != "":
+		return nil, errors.New("ApiKeyParameter and ApiKeySecret are mutually exclusive")
+	case parameter != "":
+		key = "parameter:" + parameter
+	default:
+		key = "secret:" + secret
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if g, ok := f.pool[key]; ok {
+		return g, nil
+	}
+	if f.APIKeyResolver == nil {
+		return nil, errors.New("ApiKeyParameter and ApiKeySecret are not supported")
+	}
+
+	var provider mackerel.APIKeyProvider
+	if parameter != "" {
+		provider = f.APIKeyResolver.ParameterProvider(parameter)
+	} else {
+		provider = f.APIKeyResolver.SecretProvider(secret)
+	}
+	g := &Function{
+		APIKeyProvider: provider,
+		APIKeyTTL:      f.APIKeyTTL,
+		BaseURL:        f.BaseURL,
+		Version:        f.Version,
+		now:            f.now,
+	}
+	if f.pool == nil {
+		f.pool = make(map[string]*Function)
+	}
+	f.pool[key] = g
+	return g, nil
+}
+
+// movesOrg reports whether the update moves the resource to another organization
+// by changing ApiKeyParameter or ApiKeySecret.
+// The resource can't be updated in place in that case, so it should be replaced.
+func (f *Function) movesOrg(ctx context.Context, event cfn.Event) (bool, error) {
+	if event.RequestType != cfn.RequestUpdate {
+		return false, nil
+	}
+	if apiKeyOf(event.OldResourceProperties) == apiKeyOf(event.ResourceProperties) {
+		return false, nil
+	}
+
+	ids := strings.Split(event.PhysicalResourceID, ":")
+	if len(ids) < 2 || ids[0] != "mkr" {
+		return false, nil
+	}
+	org, err := f.getorg(ctx)
+	if err != nil {
+		return false, err
+	}
+	return ids[1] != org.Name, nil
+}
+
+// apiKeyOf returns ApiKeyParameter and ApiKeySecret of the properties in a comparable form.
+func apiKeyOf(properties map[string]any) string {
+	parameter, _ := properties["ApiKeyParameter"].(string)
+	secret, _ := properties["ApiKeySecret"].(string)
+	return "parameter:" + parameter + "\x00secret:" + secret
+}
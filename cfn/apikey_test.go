@@ -0,0 +1,200 @@
+package cfn
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/mackereltest"
+)
+
+type fakeResolver struct {
+	parameters map[string]string
+	secrets    map[string]string
+	calls      int
+}
+
+func (r *fakeResolver) ParameterProvider(name string) mackerel.APIKeyProvider {
+	r.calls++
+	return apikey.NewStatic(r.parameters[name])
+}
+
+func (r *fakeResolver) SecretProvider(secretID string) mackerel.APIKeyProvider {
+	r.calls++
+	return apikey.NewStatic(r.secrets[secretID])
+}
+
+func TestForResource(t *testing.T) {
+	resolver := &fakeResolver{}
+	f := &Function{
+		APIKeyResolver: resolver,
+	}
+	forResource := func(properties map[string]any) (*Function, error) {
+		return f.forResource(cfn.Event{
+			ResourceProperties: properties,
+		})
+	}
+
+	g, err := forResource(map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g != f {
+		t.Error("want the default function, got another one")
+	}
+
+	staging, err := forResource(map[string]any{"ApiKeySecret": "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging == f {
+		t.Error("want the function for the secret, got the default one")
+	}
+	g, err = forResource(map[string]any{"ApiKeySecret": "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g != staging {
+		t.Error("want the cached function, got another one")
+	}
+	g, err = forResource(map[string]any{"ApiKeyParameter": "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g == staging {
+		t.Error("want the function for the parameter, got the one for the secret")
+	}
+	if resolver.calls != 2 {
+		t.Errorf("unexpected call count of the resolver: want %d, got %d", 2, resolver.calls)
+	}
+
+	_, err = forResource(map[string]any{"ApiKeyParameter": "staging", "ApiKeySecret": "staging"})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+
+	f = &Function{}
+	_, err = forResource(map[string]any{"ApiKeySecret": "staging"})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestHandle_apiKeySecret(t *testing.T) {
+	srv := mackereltest.NewServer()
+	defer srv.Close()
+	srv.SetAPIKey("staging-api-key")
+	srv.SetOrgName("staging")
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Function{
+		APIKey:  "production-api-key",
+		BaseURL: u,
+		APIKeyResolver: &fakeResolver{
+			secrets: map[string]string{"staging": "staging-api-key"},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id",
+		ResourceType:      "Custom::Service",
+		LogicalResourceID: "Service",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name":         "awesome-service",
+			"ApiKeySecret": "staging",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:staging:service:awesome-service" {
+		t.Errorf("unexpected service id: want %s, got %s", "mkr:staging:service:awesome-service", id)
+	}
+
+	// the role refers to the service in the staging organization.
+	event = cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id",
+		ResourceType:      "Custom::Role",
+		LogicalResourceID: "Role",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service":      id,
+			"Name":         "awesome-role",
+			"ApiKeySecret": "staging",
+		},
+	}
+	id, _, err = f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:staging:role:awesome-service:awesome-role" {
+		t.Errorf("unexpected role id: want %s, got %s", "mkr:staging:role:awesome-service:awesome-role", id)
+	}
+
+	// the default api key is not for the staging organization.
+	delete(event.ResourceProperties, "ApiKeySecret")
+	if _, _, err := f.Handle(context.Background(), event); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestHandle_moveOrg(t *testing.T) {
+	srv := mackereltest.NewServer()
+	defer srv.Close()
+	srv.SetAPIKey("staging-api-key")
+	srv.SetOrgName("staging")
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Function{
+		APIKey:  "production-api-key",
+		BaseURL: u,
+		APIKeyResolver: &fakeResolver{
+			secrets: map[string]string{"staging": "staging-api-key"},
+		},
+		org: &mackerel.Org{Name: "production"},
+	}
+
+	// the service is moved from the production organization to the staging one.
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id",
+		ResourceType:       "Custom::Service",
+		LogicalResourceID:  "Service",
+		PhysicalResourceID: "mkr:production:service:awesome-service",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name":         "awesome-service",
+			"ApiKeySecret": "staging",
+		},
+		OldResourceProperties: map[string]any{
+			"Name": "awesome-service",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:staging:service:awesome-service" {
+		t.Errorf("unexpected service id: want %s, got %s", "mkr:staging:service:awesome-service", id)
+	}
+	services, err := srv.Client().FindServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Name != "awesome-service" {
+		t.Errorf("the service is not created in the staging organization: %#v", services)
+	}
+}
@@ -53,7 +53,7 @@ func (r *awsIntegration) update(ctx context.Context) (physicalResourceID string,
 
 func (r *awsIntegration) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.AWSIntegration, error) {
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 
 	var externalID *string
 	if v := d.OptionalString(in.M("ExternalId")); v != nil {
@@ -75,6 +75,7 @@ func (r *awsIntegration) convertToParam(ctx context.Context, properties map[stri
 		Services:     r.convertAWSServices(ctx, &d, in.M("Services")),
 	}
 
+	checkUnknownProperties(&d, r.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
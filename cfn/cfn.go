@@ -16,12 +16,19 @@ import (
 type Function struct {
 	APIKey         string
 	APIKeyProvider mackerel.APIKeyProvider
+	APIKeyTTL      time.Duration
 	BaseURL        *url.URL
 	Version        string
 
+	// APIKeyResolver resolves the api keys that resources specify.
+	// If it is nil, the ApiKeyParameter and ApiKeySecret properties are not supported.
+	APIKeyResolver APIKeyResolver
+
 	mu     sync.Mutex
 	client makerelInterface
 	org    *mackerel.Org
+	pool   map[string]*Function // the functions for the api keys of resources
+	now    func() time.Time     // it is replaced in tests
 }
 
 type makerelInterface interface {
@@ -29,8 +36,11 @@ type makerelInterface interface {
 	GetOrg(ctx context.Context) (*mackerel.Org, error)
 
 	// host
+	FindHost(ctx context.Context, hostID string) (*mackerel.Host, error)
 	CreateHost(ctx context.Context, param *mackerel.CreateHostParam) (string, error)
 	UpdateHost(ctx context.Context, hostID string, param *mackerel.UpdateHostParam) (string, error)
+	UpdateHostStatus(ctx context.Context, hostID, status string) error
+	UpdateHostRoleFullnames(ctx context.Context, hostID string, roleFullnames []string) error
 	RetireHost(ctx context.Context, id string) error
 
 	// host metadata
@@ -40,6 +50,8 @@ type makerelInterface interface {
 	DeleteHostMetaData(ctx context.Context, hostID, namespace string) error
 
 	// monitor
+	FindMonitors(ctx context.Context) ([]mackerel.Monitor, error)
+	FindMonitor(ctx context.Context, monitorID string) (mackerel.Monitor, error)
 	CreateMonitor(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error)
 	UpdateMonitor(ctx context.Context, monitorID string, param mackerel.Monitor) (mackerel.Monitor, error)
 	DeleteMonitor(ctx context.Context, monitorID string) (mackerel.Monitor, error)
@@ -53,6 +65,7 @@ type makerelInterface interface {
 
 	// role
 	CreateRole(ctx context.Context, serviceName string, param *mackerel.CreateRoleParam) (*mackerel.Role, error)
+	UpdateRole(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error)
 	DeleteRole(ctx context.Context, serviceName, roleName string) (*mackerel.Role, error)
 
 	// role metadata
@@ -63,6 +76,7 @@ type makerelInterface interface {
 
 	// service
 	CreateService(ctx context.Context, param *mackerel.CreateServiceParam) (*mackerel.Service, error)
+	UpdateService(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error)
 	DeleteService(ctx context.Context, serviceName string) (*mackerel.Service, error)
 
 	// service metadata
@@ -105,6 +119,22 @@ type makerelInterface interface {
 	DeleteAWSIntegration(ctx context.Context, awsIntegrationID string) (*mackerel.AWSIntegration, error)
 	CreateAWSIntegrationExternalID(ctx context.Context) (string, error)
 	FindAWSIntegrationsExcludableMetrics(ctx context.Context) (map[string][]string, error)
+
+	// graph definition
+	CreateGraphDefinitions(ctx context.Context, defs []*mackerel.GraphDefinition) error
+	DeleteGraphDefinition(ctx context.Context, name string) error
+
+	// graph annotation
+	FindGraphAnnotations(ctx context.Context, service string, from, to int64) ([]*mackerel.GraphAnnotation, error)
+	CreateGraphAnnotation(ctx context.Context, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error)
+	UpdateGraphAnnotation(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error)
+	DeleteGraphAnnotation(ctx context.Context, annotationID string) (*mackerel.GraphAnnotation, error)
+
+	// alert group setting
+	FindAlertGroupSetting(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error)
+	CreateAlertGroupSetting(ctx context.Context, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error)
+	UpdateAlertGroupSetting(ctx context.Context, settingID string, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error)
+	DeleteAlertGroupSetting(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error)
 }
 
 type resource interface {
@@ -127,6 +157,11 @@ func (f *Function) Handle(ctx context.Context, event cfn.Event) (physicalResourc
 		defer cancel()
 	}
 
+	f, err = f.forResource(event)
+	if err != nil {
+		return "mkr::error:" + event.RequestID, nil, err
+	}
+
 	typ := strings.TrimPrefix(event.ResourceType, "Custom::")
 	var r resource
 	switch typ {
@@ -190,6 +225,26 @@ func (f *Function) Handle(ctx context.Context, event cfn.Event) (physicalResourc
 			Function: f,
 			Event:    event,
 		}
+	case "GraphDefinition":
+		r = &graphDefinition{
+			Function: f,
+			Event:    event,
+		}
+	case "GraphAnnotation":
+		r = &graphAnnotation{
+			Function: f,
+			Event:    event,
+		}
+	case "AlertGroupSetting":
+		r = &alertGroupSetting{
+			Function: f,
+			Event:    event,
+		}
+	case "Metadata":
+		r = &metadataResource{
+			Function: f,
+			Event:    event,
+		}
 	default:
 		return "", nil, nil // fmt.Errorf("unknown type: %s", typ)
 	}
@@ -197,7 +252,18 @@ func (f *Function) Handle(ctx context.Context, event cfn.Event) (physicalResourc
 	case cfn.RequestCreate:
 		physicalResourceID, data, err = r.create(ctx)
 	case cfn.RequestUpdate:
-		physicalResourceID, data, err = r.update(ctx)
+		var moved bool
+		moved, err = f.movesOrg(ctx, event)
+		if err != nil {
+			break
+		}
+		if moved {
+			// create a new resource in the new organization,
+			// and CloudFormation deletes the old one with the old api key.
+			physicalResourceID, data, err = r.create(ctx)
+		} else {
+			physicalResourceID, data, err = r.update(ctx)
+		}
 	case cfn.RequestDelete:
 		physicalResourceID, data, err = r.delete(ctx)
 	default:
@@ -217,6 +283,14 @@ func (f *Function) LambdaWrap() cfn.CustomResourceLambdaFunction {
 	return cfn.LambdaWrap(f.Handle)
 }
 
+// currentTime returns the current time.
+func (f *Function) currentTime() time.Time {
+	if f.now != nil {
+		return f.now()
+	}
+	return time.Now()
+}
+
 func (f *Function) getclient() makerelInterface {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -224,6 +298,7 @@ func (f *Function) getclient() makerelInterface {
 		c := &mackerel.Client{
 			APIKey:         f.APIKey,
 			APIKeyProvider: f.APIKeyProvider,
+			APIKeyTTL:      f.APIKeyTTL,
 			BaseURL:        f.BaseURL,
 		}
 		if f.Version != "" {
@@ -302,6 +377,24 @@ func (f *Function) buildAWSIntegrationExternalID(ctx context.Context, awsIntegra
 	return f.buildID(ctx, "aws-integration-external-id", awsIntegrationExternalID)
 }
 
+func (f *Function) buildGraphDefinitionID(ctx context.Context, name string) (string, error) {
+	return f.buildID(ctx, "graph-def", name)
+}
+
+func (f *Function) buildGraphAnnotationID(ctx context.Context, annotationID string) (string, error) {
+	return f.buildID(ctx, "graph-annotation", annotationID)
+}
+
+func (f *Function) buildAlertGroupSettingID(ctx context.Context, settingID string) (string, error) {
+	return f.buildID(ctx, "alert-group-setting", settingID)
+}
+
+func (f *Function) buildMetadataID(ctx context.Context, target metadataTarget, namespace string) (string, error) {
+	ids := append([]string{target.Type}, target.IDs...)
+	ids = append(ids, namespace)
+	return f.buildID(ctx, "metadata", ids...)
+}
+
 // parseID parses ID of Mackerel resources.
 func (f *Function) parseID(ctx context.Context, id string, n int) (string, []string, error) {
 	org, err := f.getorg(ctx)
@@ -443,6 +536,108 @@ func (f *Function) parseAWSIntegrationExternalID(ctx context.Context, id string)
 	return parts[0], nil
 }
 
+func (f *Function) parseGraphDefinitionID(ctx context.Context, id string) (string, error) {
+	typ, parts, err := f.parseID(ctx, id, 1)
+	if err != nil {
+		return "", err
+	}
+	if typ != "graph-def" {
+		return "", fmt.Errorf("invalid type %s, expected graph-def", typ)
+	}
+	return parts[0], nil
+}
+
+func (f *Function) parseGraphAnnotationID(ctx context.Context, id string) (string, error) {
+	typ, parts, err := f.parseID(ctx, id, 1)
+	if err != nil {
+		return "", err
+	}
+	if typ != "graph-annotation" {
+		return "", fmt.Errorf("invalid type %s, expected graph-annotation", typ)
+	}
+	return parts[0], nil
+}
+
+func (f *Function) parseAlertGroupSettingID(ctx context.Context, id string) (string, error) {
+	typ, parts, err := f.parseID(ctx, id, 1)
+	if err != nil {
+		return "", err
+	}
+	if typ != "alert-group-setting" {
+		return "", fmt.Errorf("invalid type %s, expected alert-group-setting", typ)
+	}
+	return parts[0], nil
+}
+
+// metadataTarget is a resource that has metadata.
+type metadataTarget struct {
+	// Type is one of "service", "role" and "host".
+	Type string
+
+	// IDs are the service name for services,
+	// the service name and the role name for roles,
+	// and the host id for hosts.
+	IDs []string
+}
+
+// parseMetadataTarget parses the ID of a service, a role or a host.
+func (f *Function) parseMetadataTarget(ctx context.Context, id string) (metadataTarget, error) {
+	typ, _, err := f.parseID(ctx, id, 1)
+	if err != nil {
+		return metadataTarget{}, err
+	}
+	switch typ {
+	case "service":
+		serviceName, err := f.parseServiceID(ctx, id)
+		if err != nil {
+			return metadataTarget{}, err
+		}
+		return metadataTarget{Type: typ, IDs: []string{serviceName}}, nil
+	case "role":
+		serviceName, roleName, err := f.parseRoleID(ctx, id)
+		if err != nil {
+			return metadataTarget{}, err
+		}
+		return metadataTarget{Type: typ, IDs: []string{serviceName, roleName}}, nil
+	case "host":
+		hostID, err := f.parseHostID(ctx, id)
+		if err != nil {
+			return metadataTarget{}, err
+		}
+		return metadataTarget{Type: typ, IDs: []string{hostID}}, nil
+	}
+	return metadataTarget{}, fmt.Errorf("invalid type %s, expected service, role or host type", typ)
+}
+
+func (f *Function) parseMetadataID(ctx context.Context, id string) (metadataTarget, string, error) {
+	typ, parts, err := f.parseID(ctx, id, 3)
+	if err != nil {
+		return metadataTarget{}, "", err
+	}
+	if typ != "metadata" {
+		return metadataTarget{}, "", fmt.Errorf("invalid type %s, expected metadata", typ)
+	}
+
+	target := metadataTarget{
+		Type: parts[0],
+		IDs:  parts[1 : len(parts)-1],
+	}
+	namespace := parts[len(parts)-1]
+	var n int
+	switch target.Type {
+	case "service", "host":
+		n = 1
+	case "role":
+		n = 2
+	default:
+		return metadataTarget{}, "", fmt.Errorf("invalid metadata target type: %s", target.Type)
+	}
+	if len(target.IDs) != n {
+		return metadataTarget{}, "", fmt.Errorf("invalid mkr id: %s", id)
+	}
+	return target, namespace, nil
+}
+
 type metadata struct {
 	StackName string `json:"stack_name"`
 	StackID   string `json:"stack_id"`
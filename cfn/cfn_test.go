@@ -9,13 +9,18 @@ import (
 
 type fakeMackerelClient struct {
 	getOrg                               func(ctx context.Context) (*mackerel.Org, error)
+	findHost                             func(ctx context.Context, hostID string) (*mackerel.Host, error)
 	createHost                           func(ctx context.Context, param *mackerel.CreateHostParam) (string, error)
 	updateHost                           func(ctx context.Context, hostID string, param *mackerel.UpdateHostParam) (string, error)
+	updateHostStatus                     func(ctx context.Context, hostID, status string) error
+	updateHostRoleFullnames              func(ctx context.Context, hostID string, roleFullnames []string) error
 	retireHost                           func(ctx context.Context, id string) error
 	getHostMetaData                      func(ctx context.Context, hostID, namespace string, v any) (*mackerel.HostMetaMetaData, error)
 	getHostMetaDataNameSpaces            func(ctx context.Context, hostID string) ([]string, error)
 	putHostMetaData                      func(ctx context.Context, hostID, namespace string, v any) error
 	deleteHostMetaData                   func(ctx context.Context, hostID, namespace string) error
+	findMonitors                         func(ctx context.Context) ([]mackerel.Monitor, error)
+	findMonitor                          func(ctx context.Context, monitorID string) (mackerel.Monitor, error)
 	createMonitor                        func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error)
 	updateMonitor                        func(ctx context.Context, monitorID string, param mackerel.Monitor) (mackerel.Monitor, error)
 	deleteMonitor                        func(ctx context.Context, monitorID string) (mackerel.Monitor, error)
@@ -25,12 +30,14 @@ type fakeMackerelClient struct {
 	updateDashboard                      func(ctx context.Context, dashboardID string, param *mackerel.Dashboard) (*mackerel.Dashboard, error)
 	deleteDashboard                      func(ctx context.Context, dashboardID string) (*mackerel.Dashboard, error)
 	createRole                           func(ctx context.Context, serviceName string, param *mackerel.CreateRoleParam) (*mackerel.Role, error)
+	updateRole                           func(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error)
 	deleteRole                           func(ctx context.Context, serviceName, roleName string) (*mackerel.Role, error)
 	getRoleMetaData                      func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error)
 	getRoleMetaDataNameSpaces            func(ctx context.Context, serviceName, roleName string) ([]string, error)
 	putRoleMetaData                      func(ctx context.Context, serviceName, roleName, namespace string, v any) error
 	deleteRoleMetaData                   func(ctx context.Context, serviceName, roleName, namespace string) error
 	createService                        func(ctx context.Context, param *mackerel.CreateServiceParam) (*mackerel.Service, error)
+	updateService                        func(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error)
 	deleteService                        func(ctx context.Context, serviceName string) (*mackerel.Service, error)
 	getServiceMetaData                   func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error)
 	getServiceMetaDataNameSpaces         func(ctx context.Context, serviceName string) ([]string, error)
@@ -59,6 +66,16 @@ type fakeMackerelClient struct {
 	deleteAWSIntegration                 func(ctx context.Context, awsIntegrationID string) (*mackerel.AWSIntegration, error)
 	createAWSIntegrationExternalID       func(ctx context.Context) (string, error)
 	findAWSIntegrationsExcludableMetrics func(ctx context.Context) (map[string][]string, error)
+	createGraphDefinitions               func(ctx context.Context, defs []*mackerel.GraphDefinition) error
+	deleteGraphDefinition                func(ctx context.Context, name string) error
+	findGraphAnnotations                 func(ctx context.Context, service string, from, to int64) ([]*mackerel.GraphAnnotation, error)
+	createGraphAnnotation                func(ctx context.Context, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error)
+	updateGraphAnnotation                func(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error)
+	deleteGraphAnnotation                func(ctx context.Context, annotationID string) (*mackerel.GraphAnnotation, error)
+	findAlertGroupSetting                func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error)
+	createAlertGroupSetting              func(ctx context.Context, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error)
+	updateAlertGroupSetting              func(ctx context.Context, settingID string, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error)
+	deleteAlertGroupSetting              func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error)
 }
 
 var _ makerelInterface = (*fakeMackerelClient)(nil)
@@ -67,6 +84,10 @@ func (c *fakeMackerelClient) GetOrg(ctx context.Context) (*mackerel.Org, error)
 	return c.getOrg(ctx)
 }
 
+func (c *fakeMackerelClient) FindHost(ctx context.Context, hostID string) (*mackerel.Host, error) {
+	return c.findHost(ctx, hostID)
+}
+
 func (c *fakeMackerelClient) CreateHost(ctx context.Context, param *mackerel.CreateHostParam) (string, error) {
 	return c.createHost(ctx, param)
 }
@@ -75,6 +96,14 @@ func (c *fakeMackerelClient) UpdateHost(ctx context.Context, hostID string, para
 	return c.updateHost(ctx, hostID, param)
 }
 
+func (c *fakeMackerelClient) UpdateHostStatus(ctx context.Context, hostID, status string) error {
+	return c.updateHostStatus(ctx, hostID, status)
+}
+
+func (c *fakeMackerelClient) UpdateHostRoleFullnames(ctx context.Context, hostID string, roleFullnames []string) error {
+	return c.updateHostRoleFullnames(ctx, hostID, roleFullnames)
+}
+
 func (c *fakeMackerelClient) RetireHost(ctx context.Context, id string) error {
 	return c.retireHost(ctx, id)
 }
@@ -95,6 +124,14 @@ func (c *fakeMackerelClient) DeleteHostMetaData(ctx context.Context, hostID, nam
 	return c.deleteHostMetaData(ctx, hostID, namespace)
 }
 
+func (c *fakeMackerelClient) FindMonitors(ctx context.Context) ([]mackerel.Monitor, error) {
+	return c.findMonitors(ctx)
+}
+
+func (c *fakeMackerelClient) FindMonitor(ctx context.Context, monitorID string) (mackerel.Monitor, error) {
+	return c.findMonitor(ctx, monitorID)
+}
+
 func (c *fakeMackerelClient) CreateMonitor(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 	return c.createMonitor(ctx, param)
 }
@@ -130,6 +167,10 @@ func (c *fakeMackerelClient) CreateRole(ctx context.Context, serviceName string,
 	return c.createRole(ctx, serviceName, param)
 }
 
+func (c *fakeMackerelClient) UpdateRole(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error) {
+	return c.updateRole(ctx, serviceName, roleName, param)
+}
+
 func (c *fakeMackerelClient) DeleteRole(ctx context.Context, serviceName, roleName string) (*mackerel.Role, error) {
 	return c.deleteRole(ctx, serviceName, roleName)
 }
@@ -154,6 +195,10 @@ func (c *fakeMackerelClient) CreateService(ctx context.Context, param *mackerel.
 	return c.createService(ctx, param)
 }
 
+func (c *fakeMackerelClient) UpdateService(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error) {
+	return c.updateService(ctx, serviceName, param)
+}
+
 func (c *fakeMackerelClient) DeleteService(ctx context.Context, serviceName string) (*mackerel.Service, error) {
 	return c.deleteService(ctx, serviceName)
 }
@@ -265,6 +310,46 @@ func (c *fakeMackerelClient) FindAWSIntegrationsExcludableMetrics(ctx context.Co
 	return c.findAWSIntegrationsExcludableMetrics(ctx)
 }
 
+func (c *fakeMackerelClient) CreateGraphDefinitions(ctx context.Context, defs []*mackerel.GraphDefinition) error {
+	return c.createGraphDefinitions(ctx, defs)
+}
+
+func (c *fakeMackerelClient) DeleteGraphDefinition(ctx context.Context, name string) error {
+	return c.deleteGraphDefinition(ctx, name)
+}
+
+func (c *fakeMackerelClient) FindGraphAnnotations(ctx context.Context, service string, from, to int64) ([]*mackerel.GraphAnnotation, error) {
+	return c.findGraphAnnotations(ctx, service, from, to)
+}
+
+func (c *fakeMackerelClient) CreateGraphAnnotation(ctx context.Context, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+	return c.createGraphAnnotation(ctx, param)
+}
+
+func (c *fakeMackerelClient) UpdateGraphAnnotation(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+	return c.updateGraphAnnotation(ctx, annotationID, param)
+}
+
+func (c *fakeMackerelClient) DeleteGraphAnnotation(ctx context.Context, annotationID string) (*mackerel.GraphAnnotation, error) {
+	return c.deleteGraphAnnotation(ctx, annotationID)
+}
+
+func (c *fakeMackerelClient) FindAlertGroupSetting(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+	return c.findAlertGroupSetting(ctx, settingID)
+}
+
+func (c *fakeMackerelClient) CreateAlertGroupSetting(ctx context.Context, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error) {
+	return c.createAlertGroupSetting(ctx, param)
+}
+
+func (c *fakeMackerelClient) UpdateAlertGroupSetting(ctx context.Context, settingID string, param *mackerel.AlertGroupSetting) (*mackerel.AlertGroupSetting, error) {
+	return c.updateAlertGroupSetting(ctx, settingID, param)
+}
+
+func (c *fakeMackerelClient) DeleteAlertGroupSetting(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+	return c.deleteAlertGroupSetting(ctx, settingID)
+}
+
 type mkrError struct {
 	statusCode int
 	message    string
@@ -23,7 +23,16 @@ func (r *dashboard) create(ctx context.Context) (physicalResourceID string, data
 	if err != nil {
 		return "", nil, err
 	}
-	ret, err := c.CreateDashboard(ctx, param)
+	adopteeID, err := r.findAdoptee(ctx, param)
+	if err != nil {
+		return "", nil, err
+	}
+	var ret *mackerel.Dashboard
+	if adopteeID != "" {
+		ret, err = c.UpdateDashboard(ctx, adopteeID, param)
+	} else {
+		ret, err = c.CreateDashboard(ctx, param)
+	}
 	if err != nil {
 		return "", nil, err
 	}
@@ -35,6 +44,39 @@ func (r *dashboard) create(ctx context.Context) (physicalResourceID string, data
 	return id, map[string]any{}, nil
 }
 
+// findAdoptee returns the id of the existing dashboard that has the same title.
+// It returns an empty string if a new dashboard should be created.
+func (r *dashboard) findAdoptee(ctx context.Context, param *mackerel.Dashboard) (string, error) {
+	if adopt, err := shouldAdopt(r.Event); err != nil || !adopt {
+		return "", err
+	}
+	c := r.Function.getclient()
+	dashboards, err := c.FindDashboards(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, err := findByName("dashboard", param.Title, dashboards, func(v *mackerel.Dashboard) (string, string) {
+		return v.Title, v.ID
+	})
+	if err != nil || id == "" {
+		return "", err
+	}
+	for _, v := range dashboards {
+		if v.ID == id {
+			if err := r.checkOwner(v); err != nil {
+				return "", err
+			}
+		}
+	}
+	return id, nil
+}
+
+// checkOwner returns an error if the dashboard is owned by another stack.
+func (r *dashboard) checkOwner(dashboard *mackerel.Dashboard) error {
+	resource := fmt.Sprintf("the dashboard %s", dashboard.ID)
+	return checkOwner(r.Event, resource, ownerFromMemo(dashboard.Memo))
+}
+
 func (r *dashboard) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	c := r.Function.getclient()
 	param, err := r.convertToParam(ctx, r.Event.ResourceProperties)
@@ -45,6 +87,13 @@ func (r *dashboard) update(ctx context.Context) (physicalResourceID string, data
 	if err != nil {
 		return r.Event.PhysicalResourceID, nil, err
 	}
+	current, err := c.FindDashboard(ctx, id)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	if err := r.checkOwner(current); err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
 	_, err = c.UpdateDashboard(ctx, id, param)
 	if err != nil {
 		return r.Event.PhysicalResourceID, nil, err
@@ -55,7 +104,7 @@ func (r *dashboard) update(ctx context.Context) (physicalResourceID string, data
 
 func (r *dashboard) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.Dashboard, error) {
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 
 	widgets := []mackerel.Widget{}
 	for _, w := range d.ProxyArray(in.M("Widgets").ProxySet()) {
@@ -64,10 +113,11 @@ func (r *dashboard) convertToParam(ctx context.Context, properties map[string]an
 
 	param := &mackerel.Dashboard{
 		Title:   d.String(in.M("Title")),
-		Memo:    d.String(dproxy.Default(in.M("Memo"), "")),
+		Memo:    withOwner(d.String(dproxy.Default(in.M("Memo"), "")), r.Event),
 		URLPath: d.String(in.M("UrlPath")),
 		Widgets: widgets,
 	}
+	checkUnknownProperties(&d, r.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
@@ -238,8 +288,21 @@ func (r *dashboard) delete(ctx context.Context) (physicalResourceID string, data
 	}
 
 	c := r.Function.getclient()
-	_, err = c.DeleteDashboard(ctx, id)
 	var merr mackerel.Error
+	current, err := c.FindDashboard(ctx, id)
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the dashboard %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	if err = r.checkOwner(current); err != nil {
+		return
+	}
+
+	_, err = c.DeleteDashboard(ctx, id)
 	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
 		log.Printf("It seems that the role %q is already deleted, ignore the error: %s", physicalResourceID, err)
 		err = nil
@@ -18,7 +18,7 @@ func TestCreateDashboard(t *testing.T) {
 			createDashboard: func(ctx context.Context, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
 				want := &mackerel.Dashboard{
 					Title:   "dashboard-foobar",
-					Memo:    "memo",
+					Memo:    "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					URLPath: "my-dashboard",
 					Widgets: []mackerel.Widget{
 
@@ -360,13 +360,22 @@ func TestUpdateDashboard(t *testing.T) {
 			Name: "test-org",
 		},
 		client: &fakeMackerelClient{
+			findDashboard: func(ctx context.Context, id string) (*mackerel.Dashboard, error) {
+				return &mackerel.Dashboard{
+					ID:      id,
+					Title:   "dashboard-foobar",
+					Memo:    "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					URLPath: "my-dashboard",
+					Widgets: []mackerel.Widget{},
+				}, nil
+			},
 			updateDashboard: func(ctx context.Context, id string, param *mackerel.Dashboard) (*mackerel.Dashboard, error) {
 				if id != "dashboard-id" {
 					t.Errorf("unexpected dashboard id: want %s, got %s", "dashboard-id", id)
 				}
 				want := &mackerel.Dashboard{
 					Title:   "dashboard-foobar",
-					Memo:    "memo",
+					Memo:    "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					URLPath: "my-dashboard",
 					Widgets: []mackerel.Widget{},
 				}
@@ -392,14 +401,12 @@ func TestUpdateDashboard(t *testing.T) {
 			"Memo":    "memo",
 			"UrlPath": "my-dashboard",
 			"Widgets": []any{},
-			"Roles":   []any{},
 		},
 		ResourceProperties: map[string]any{
 			"Title":   "dashboard-foobar",
 			"Memo":    "memo",
 			"UrlPath": "my-dashboard",
 			"Widgets": []any{},
-			"Roles":   []any{},
 		},
 	}
 	id, _, err := f.Handle(context.Background(), event)
@@ -417,6 +424,15 @@ func TestDeleteDashboard(t *testing.T) {
 			Name: "test-org",
 		},
 		client: &fakeMackerelClient{
+			findDashboard: func(ctx context.Context, id string) (*mackerel.Dashboard, error) {
+				return &mackerel.Dashboard{
+					ID:      id,
+					Title:   "dashboard-foobar",
+					Memo:    "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					URLPath: "my-dashboard",
+					Widgets: []mackerel.Widget{},
+				}, nil
+			},
 			deleteDashboard: func(ctx context.Context, id string) (*mackerel.Dashboard, error) {
 				if id != "dashboard-id" {
 					t.Errorf("unexpected dashboard id: want %s, got %s", "dashboard-id", id)
@@ -444,7 +460,6 @@ func TestDeleteDashboard(t *testing.T) {
 			"Memo":    "memo",
 			"UrlPath": "my-dashboard",
 			"Widgets": []any{},
-			"Roles":   []any{},
 		},
 	}
 	id, _, err := f.Handle(context.Background(), event)
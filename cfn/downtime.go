@@ -23,7 +23,16 @@ func (r *downtime) create(ctx context.Context) (physicalResourceID string, data
 	if err != nil {
 		return
 	}
-	ret, err := c.CreateDowntime(ctx, param)
+	adopteeID, err := r.findAdoptee(ctx, param)
+	if err != nil {
+		return
+	}
+	var ret *mackerel.Downtime
+	if adopteeID != "" {
+		ret, err = c.UpdateDowntime(ctx, adopteeID, param)
+	} else {
+		ret, err = c.CreateDowntime(ctx, param)
+	}
 	if err != nil {
 		return
 	}
@@ -32,6 +41,41 @@ func (r *downtime) create(ctx context.Context) (physicalResourceID string, data
 	return
 }
 
+// findAdoptee returns the id of the existing downtime that has the same name.
+// It returns an empty string if a new downtime should be created.
+func (r *downtime) findAdoptee(ctx context.Context, param *mackerel.Downtime) (string, error) {
+	if adopt, err := shouldAdopt(r.Event); err != nil || !adopt {
+		return "", err
+	}
+	c := r.Function.getclient()
+	downtimes, err := c.FindDowntimes(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, err := findByName("downtime", param.Name, downtimes, func(v *mackerel.Downtime) (string, string) {
+		return v.Name, v.ID
+	})
+	if err != nil || id == "" {
+		return "", err
+	}
+	if err := r.checkOwner(downtimes, id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// checkOwner returns an error if the downtime is owned by another stack.
+// Mackerel has no API for getting a single downtime, so it is searched in downtimes.
+func (r *downtime) checkOwner(downtimes []*mackerel.Downtime, id string) error {
+	for _, v := range downtimes {
+		if v.ID == id {
+			resource := fmt.Sprintf("the downtime %s", v.ID)
+			return checkOwner(r.Event, resource, ownerFromMemo(v.Memo))
+		}
+	}
+	return nil
+}
+
 func (r *downtime) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	c := r.Function.getclient()
 	physicalResourceID = r.Event.PhysicalResourceID
@@ -43,6 +87,13 @@ func (r *downtime) update(ctx context.Context) (physicalResourceID string, data
 	if err != nil {
 		return
 	}
+	downtimes, err := c.FindDowntimes(ctx)
+	if err != nil {
+		return
+	}
+	if err = r.checkOwner(downtimes, id); err != nil {
+		return
+	}
 	_, err = c.UpdateDowntime(ctx, id, param)
 	return
 }
@@ -50,10 +101,10 @@ func (r *downtime) update(ctx context.Context) (physicalResourceID string, data
 func (r *downtime) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.Downtime, error) {
 	var param mackerel.Downtime
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 
 	param.Name = d.String(in.M("Name"))
-	param.Memo = d.String(dproxy.Default(in.M("Memo"), ""))
+	param.Memo = withOwner(d.String(dproxy.Default(in.M("Memo"), "")), r.Event)
 	param.Start = mackerel.Timestamp(d.Int64(in.M("Start")))
 	param.Duration = d.Int64(in.M("Duration"))
 
@@ -96,7 +147,7 @@ func (r *downtime) convertToParam(ctx context.Context, properties map[string]any
 			Type:     typ,
 			Interval: d.Int64(recurrence.M("Interval")),
 			Weekdays: weekdays,
-			Until:    mackerel.Timestamp(d.Int64(dproxy.Default(in.M("Until"), 0))),
+			Until:    mackerel.Timestamp(d.Int64(dproxy.Default(recurrence.M("Until"), 0))),
 		}
 	} else if !dproxy.IsErrorCode(err, dproxy.ErrorCodeNotFound) {
 		d.Put(err)
@@ -180,6 +231,7 @@ func (r *downtime) convertToParam(ctx context.Context, properties map[string]any
 		d.Put(err)
 	}
 
+	checkUnknownProperties(&d, r.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
@@ -195,6 +247,13 @@ func (r *downtime) delete(ctx context.Context) (physicalResourceID string, data
 		err = nil // ignore it
 		return
 	}
+	downtimes, err := c.FindDowntimes(ctx)
+	if err != nil {
+		return
+	}
+	if err = r.checkOwner(downtimes, id); err != nil {
+		return
+	}
 	_, err = c.DeleteDowntime(ctx, id)
 	var merr mackerel.Error
 	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
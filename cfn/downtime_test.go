@@ -20,7 +20,7 @@ func TestCreateDowntime(t *testing.T) {
 				createDowntime: func(ctx context.Context, param *mackerel.Downtime) (*mackerel.Downtime, error) {
 					want := &mackerel.Downtime{
 						Name:     "Maintenance #1",
-						Memo:     "Memo #1",
+						Memo:     "Memo #1\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 						Start:    1563700000,
 						Duration: 60,
 						Recurrence: &mackerel.DowntimeRecurrence{
@@ -142,3 +142,49 @@ func TestCreateDowntime(t *testing.T) {
 
 func TestDeleteDowntime(t *testing.T) {
 }
+
+func TestCreateDowntime_recurrenceUntil(t *testing.T) {
+	r := &downtime{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createDowntime: func(ctx context.Context, param *mackerel.Downtime) (*mackerel.Downtime, error) {
+					want := &mackerel.DowntimeRecurrence{
+						Type:     mackerel.DowntimeRecurrenceTypeDaily,
+						Interval: 1,
+						Until:    1564000000,
+					}
+					if diff := cmp.Diff(param.Recurrence, want); diff != "" {
+						t.Errorf("recurrence differs: (-got +want)\n%s", diff)
+					}
+					ret := *param
+					ret.ID = "3yAYEDLXKL5"
+					return &ret, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			RequestID:         "",
+			ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:      "Custom:Downtime",
+			LogicalResourceID: "Downtime",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name":     "Maintenance #1",
+				"Start":    1563700000,
+				"Duration": 60,
+				"Recurrence": map[string]any{
+					"Type":     "daily",
+					"Interval": 1,
+					"Until":    1564000000,
+				},
+			},
+		},
+	}
+	if _, _, err := r.create(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
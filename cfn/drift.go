@@ -0,0 +1,339 @@
+package cfn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// DriftStatus is the drift status of a resource.
+// The values are same as the ones of CloudFormation.
+type DriftStatus string
+
+const (
+	// DriftStatusInSync means the live object matches the template.
+	DriftStatusInSync DriftStatus = "IN_SYNC"
+
+	// DriftStatusModified means the live object differs from the template.
+	DriftStatusModified DriftStatus = "MODIFIED"
+
+	// DriftStatusDeleted means the live object is not found.
+	DriftStatusDeleted DriftStatus = "DELETED"
+
+	// DriftStatusNotChecked means drift detection is not supported for the resource type.
+	DriftStatusNotChecked DriftStatus = "NOT_CHECKED"
+)
+
+// DifferenceType is the type of a property difference.
+type DifferenceType string
+
+const (
+	// DifferenceTypeAdd means the value is added to the live object.
+	DifferenceTypeAdd DifferenceType = "ADD"
+
+	// DifferenceTypeRemove means the value is removed from the live object.
+	DifferenceTypeRemove DifferenceType = "REMOVE"
+
+	// DifferenceTypeNotEqual means the value of the live object differs from the template.
+	DifferenceTypeNotEqual DifferenceType = "NOT_EQUAL"
+)
+
+// ResourceDrift is the result of drift detection of a resource.
+type ResourceDrift struct {
+	LogicalResourceID  string               `json:"LogicalResourceId"`
+	PhysicalResourceID string               `json:"PhysicalResourceId"`
+	ResourceType       string               `json:"ResourceType"`
+	DriftStatus        DriftStatus          `json:"DriftStatus"`
+	Differences        []PropertyDifference `json:"PropertyDifferences,omitempty"`
+}
+
+// PropertyDifference is a difference between the template and the live object.
+type PropertyDifference struct {
+	// PropertyPath is a JSON pointer to the property of the Mackerel API object.
+	PropertyPath   string         `json:"PropertyPath"`
+	ExpectedValue  any            `json:"ExpectedValue,omitempty"`
+	ActualValue    any            `json:"ActualValue,omitempty"`
+	DifferenceType DifferenceType `json:"DifferenceType"`
+}
+
+// DetectDrift compares the live Mackerel object with the properties of the resource.
+// event describes the current state of the resource; ResourceType, LogicalResourceID,
+// PhysicalResourceID, StackID and ResourceProperties are used.
+func (f *Function) DetectDrift(ctx context.Context, event cfn.Event) (*ResourceDrift, error) {
+	ret := &ResourceDrift{
+		LogicalResourceID:  event.LogicalResourceID,
+		PhysicalResourceID: event.PhysicalResourceID,
+		ResourceType:       event.ResourceType,
+	}
+
+	f, err := f.forResource(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect drift of %s: %w", event.LogicalResourceID, err)
+	}
+
+	var desired, actual any
+	typ := strings.TrimPrefix(strings.TrimPrefix(event.ResourceType, "Custom::"), "Mackerel::")
+	switch typ {
+	case "Monitor":
+		r := &monitor{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	case "Dashboard":
+		r := &dashboard{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	case "NotificationChannel":
+		r := &notificationChannel{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	case "NotificationGroup":
+		r := &notificationGroup{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	case "Downtime":
+		r := &downtime{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	case "AlertGroupSetting":
+		r := &alertGroupSetting{Function: f, Event: event}
+		desired, actual, err = r.fetchForDrift(ctx)
+	default:
+		ret.DriftStatus = DriftStatusNotChecked
+		return ret, nil
+	}
+	var merr mackerel.Error
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		ret.DriftStatus = DriftStatusDeleted
+		return ret, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect drift of %s: %w", event.LogicalResourceID, err)
+	}
+	if actual == nil || reflect.ValueOf(actual).IsNil() {
+		ret.DriftStatus = DriftStatusDeleted
+		return ret, nil
+	}
+
+	diffs, err := diffObjects(desired, actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect drift of %s: %w", event.LogicalResourceID, err)
+	}
+	ret.Differences = diffs
+	if len(diffs) == 0 {
+		ret.DriftStatus = DriftStatusInSync
+	} else {
+		ret.DriftStatus = DriftStatusModified
+	}
+	return ret, nil
+}
+
+func (m *monitor) fetchForDrift(ctx context.Context) (mackerel.Monitor, mackerel.Monitor, error) {
+	desired, err := m.convertToParam(ctx, m.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := m.Function.parseMonitorID(ctx, m.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	actual, err := m.Function.getclient().FindMonitor(ctx, id)
+	return desired, actual, err
+}
+
+func (r *dashboard) fetchForDrift(ctx context.Context) (*mackerel.Dashboard, *mackerel.Dashboard, error) {
+	desired, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := r.Function.parseDashboardID(ctx, r.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	actual, err := r.Function.getclient().FindDashboard(ctx, id)
+	return desired, actual, err
+}
+
+func (ch *notificationChannel) fetchForDrift(ctx context.Context) (mackerel.NotificationChannel, mackerel.NotificationChannel, error) {
+	desired, err := ch.convertToParam(ctx, ch.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := ch.Function.parseNotificationChannelID(ctx, ch.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	channels, err := ch.Function.getclient().FindNotificationChannels(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range channels {
+		if v.NotificationChannelID() == id {
+			return desired, v, nil
+		}
+	}
+	return desired, nil, nil
+}
+
+func (g *notificationGroup) fetchForDrift(ctx context.Context) (*mackerel.NotificationGroup, *mackerel.NotificationGroup, error) {
+	desired, err := g.convertToParam(ctx, g.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := g.Function.parseNotificationGroupID(ctx, g.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := g.Function.getclient().FindNotificationGroups(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range groups {
+		if v.ID == id {
+			return desired, v, nil
+		}
+	}
+	return desired, nil, nil
+}
+
+func (r *downtime) fetchForDrift(ctx context.Context) (*mackerel.Downtime, *mackerel.Downtime, error) {
+	desired, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := r.Function.parseDowntimeID(ctx, r.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	downtimes, err := r.Function.getclient().FindDowntimes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range downtimes {
+		if v.ID == id {
+			return desired, v, nil
+		}
+	}
+	return desired, nil, nil
+}
+
+func (r *alertGroupSetting) fetchForDrift(ctx context.Context) (*mackerel.AlertGroupSetting, *mackerel.AlertGroupSetting, error) {
+	desired, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := r.Function.parseAlertGroupSettingID(ctx, r.Event.PhysicalResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	actual, err := r.Function.getclient().FindAlertGroupSetting(ctx, id)
+	return desired, actual, err
+}
+
+// diffObjects compares the JSON representations of the Mackerel API objects.
+// Only the properties that desired has are compared,
+// because the live objects have some properties that are filled by Mackerel, e.g. id.
+func diffObjects(desired, actual any) ([]PropertyDifference, error) {
+	want, err := toJSONValue(desired)
+	if err != nil {
+		return nil, err
+	}
+	got, err := toJSONValue(actual)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := want.(map[string]any); ok {
+		delete(m, "id")
+	}
+	var diffs []PropertyDifference
+	diffValues(&diffs, "", want, got)
+	return diffs, nil
+}
+
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var ret any
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func diffValues(diffs *[]PropertyDifference, path string, want, got any) {
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(want))
+		for k := range want {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "/" + escapeJSONPointer(k)
+			v, ok := got[k]
+			if !ok {
+				*diffs = append(*diffs, PropertyDifference{
+					PropertyPath:   p,
+					ExpectedValue:  want[k],
+					DifferenceType: DifferenceTypeRemove,
+				})
+				continue
+			}
+			diffValues(diffs, p, want[k], v)
+		}
+		return
+	case []any:
+		got, ok := got.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(want) || i < len(got); i++ {
+			p := path + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(got):
+				*diffs = append(*diffs, PropertyDifference{
+					PropertyPath:   p,
+					ExpectedValue:  want[i],
+					DifferenceType: DifferenceTypeRemove,
+				})
+			case i >= len(want):
+				*diffs = append(*diffs, PropertyDifference{
+					PropertyPath:   p,
+					ActualValue:    got[i],
+					DifferenceType: DifferenceTypeAdd,
+				})
+			default:
+				diffValues(diffs, p, want[i], got[i])
+			}
+		}
+		return
+	default:
+		if reflect.DeepEqual(want, got) {
+			return
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	*diffs = append(*diffs, PropertyDifference{
+		PropertyPath:   path,
+		ExpectedValue:  want,
+		ActualValue:    got,
+		DifferenceType: DifferenceTypeNotEqual,
+	})
+}
+
+// escapeJSONPointer escapes a reference token of JSON Pointer defined in RFC 6901.
+func escapeJSONPointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
@@ -0,0 +1,170 @@
+package cfn
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestDetectDrift_monitor(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findMonitor: func(ctx context.Context, monitorID string) (mackerel.Monitor, error) {
+				if monitorID != "3yAYEDLXKL5" {
+					t.Errorf("unexpected monitor id: want %s, got %s", "3yAYEDLXKL5", monitorID)
+				}
+				return &mackerel.MonitorConnectivity{
+					ID:   monitorID,
+					Name: "foo-bar",
+					Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					Type: mackerel.MonitorTypeConnectivity,
+					// changed in the Mackerel UI
+					NotificationInterval: 30,
+					Scopes:               []string{"service1"},
+				}, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		ResourceType:       "Custom::Monitor",
+		LogicalResourceID:  "Monitor",
+		PhysicalResourceID: "mkr:test-org:monitor:3yAYEDLXKL5",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":                 "connectivity",
+			"Name":                 "foo-bar",
+			"NotificationInterval": "60",
+			"Scopes": []any{
+				"mkr:test-org:service:service1",
+				"mkr:test-org:role:service1:role1",
+			},
+		},
+	}
+	got, err := f.DetectDrift(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ResourceDrift{
+		LogicalResourceID:  "Monitor",
+		PhysicalResourceID: "mkr:test-org:monitor:3yAYEDLXKL5",
+		ResourceType:       "Custom::Monitor",
+		DriftStatus:        DriftStatusModified,
+		Differences: []PropertyDifference{
+			{
+				PropertyPath:   "/notificationInterval",
+				ExpectedValue:  float64(60),
+				ActualValue:    float64(30),
+				DifferenceType: DifferenceTypeNotEqual,
+			},
+			{
+				PropertyPath:   "/scopes/1",
+				ExpectedValue:  "service1:role1",
+				DifferenceType: DifferenceTypeRemove,
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("drift differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDetectDrift_inSync(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findAlertGroupSetting: func(ctx context.Context, settingID string) (*mackerel.AlertGroupSetting, error) {
+				return &mackerel.AlertGroupSetting{
+					ID:            settingID,
+					Name:          "Alert Group #1",
+					Memo:          "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					MonitorScopes: []string{"monitor1"},
+				}, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		ResourceType:       "Custom::AlertGroupSetting",
+		LogicalResourceID:  "AlertGroupSetting",
+		PhysicalResourceID: "mkr:test-org:alert-group-setting:3yAYEDLXKL5",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name": "Alert Group #1",
+			"MonitorScopes": []any{
+				"mkr:test-org:monitor:monitor1",
+			},
+		},
+	}
+	got, err := f.DetectDrift(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DriftStatus != DriftStatusInSync {
+		t.Errorf("unexpected drift status: want %s, got %s: %v", DriftStatusInSync, got.DriftStatus, got.Differences)
+	}
+}
+
+func TestDetectDrift_deleted(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findDashboard: func(ctx context.Context, dashboardID string) (*mackerel.Dashboard, error) {
+				return nil, mkrError{
+					statusCode: http.StatusNotFound,
+				}
+			},
+		},
+	}
+	event := cfn.Event{
+		ResourceType:       "Custom::Dashboard",
+		LogicalResourceID:  "Dashboard",
+		PhysicalResourceID: "mkr:test-org:dashboard:dashboard-id",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Title":   "dashboard-foobar",
+			"UrlPath": "my-dashboard",
+			"Widgets": []any{},
+		},
+	}
+	got, err := f.DetectDrift(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DriftStatus != DriftStatusDeleted {
+		t.Errorf("unexpected drift status: want %s, got %s", DriftStatusDeleted, got.DriftStatus)
+	}
+}
+
+func TestDetectDrift_notChecked(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{},
+	}
+	event := cfn.Event{
+		ResourceType:       "Custom::Service",
+		LogicalResourceID:  "Service",
+		PhysicalResourceID: "mkr:test-org:service:awesome-service",
+		ResourceProperties: map[string]any{
+			"Name": "awesome-service",
+		},
+	}
+	got, err := f.DetectDrift(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DriftStatus != DriftStatusNotChecked {
+		t.Errorf("unexpected drift status: want %s, got %s", DriftStatusNotChecked, got.DriftStatus)
+	}
+}
@@ -0,0 +1,219 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+type graphAnnotation struct {
+	Function *Function
+	Event    cfn.Event
+}
+
+// the values of PeriodOnUpdate, which decides the period of the annotation on updates if From or To is omitted.
+const (
+	// periodOnUpdateKeep keeps the period that is decided when the annotation is created.
+	periodOnUpdateKeep = "keep"
+
+	// periodOnUpdateNow moves the period to the time of the update, e.g. for deploy markers.
+	periodOnUpdateNow = "now"
+)
+
+type graphAnnotationParam struct {
+	*mackerel.GraphAnnotation
+	PeriodOnUpdate string
+}
+
+func (r *graphAnnotation) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	param, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// From and To default to the time when the annotation is created.
+	now := mackerel.Timestamp(r.Function.currentTime().Unix())
+	if param.From == 0 {
+		param.From = now
+	}
+	if param.To == 0 {
+		param.To = now
+	}
+	ret, err := c.CreateGraphAnnotation(ctx, param.GraphAnnotation)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, err := r.Function.buildGraphAnnotationID(ctx, ret.ID)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, map[string]any{
+		"From": int64(ret.From),
+		"To":   int64(ret.To),
+	}, nil
+}
+
+func (r *graphAnnotation) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	id, err := r.Function.parseGraphAnnotationID(ctx, r.Event.PhysicalResourceID)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	param, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+
+	switch {
+	case param.From != 0 && param.To != 0:
+		// the period is specified explicitly.
+	case param.PeriodOnUpdate == periodOnUpdateNow:
+		now := mackerel.Timestamp(r.Function.currentTime().Unix())
+		if param.From == 0 {
+			param.From = now
+		}
+		if param.To == 0 {
+			param.To = now
+		}
+	default:
+		// keep the period that is decided when the annotation is created.
+		current, err := r.findAnnotation(ctx, id)
+		if err != nil {
+			return r.Event.PhysicalResourceID, nil, err
+		}
+		if param.From == 0 {
+			param.From = current.From
+		}
+		if param.To == 0 {
+			param.To = current.To
+		}
+	}
+	ret, err := c.UpdateGraphAnnotation(ctx, id, param.GraphAnnotation)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	return r.Event.PhysicalResourceID, map[string]any{
+		"From": int64(ret.From),
+		"To":   int64(ret.To),
+	}, nil
+}
+
+// findAnnotation finds the current annotation.
+// It usually belongs to the service in the old properties,
+// but it may belong to the new one if the previous update has failed and CloudFormation rolls it back.
+func (r *graphAnnotation) findAnnotation(ctx context.Context, annotationID string) (*mackerel.GraphAnnotation, error) {
+	c := r.Function.getclient()
+	var d dproxy.Drain
+	old := dproxy.New(r.Event.OldResourceProperties)
+	in := dproxy.New(r.Event.ResourceProperties)
+	serviceIDs := []string{d.String(old.M("Service")), d.String(in.M("Service"))}
+
+	// the period covers the stored one, which may be in the future.
+	to := max(d.Int64(dproxy.Default(old.M("To"), 0)), r.Function.currentTime().Unix())
+	if err := d.CombineErrors(); err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, serviceID := range serviceIDs {
+		service, err := r.Function.parseServiceID(ctx, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(services, service) {
+			continue
+		}
+		services = append(services, service)
+
+		annotations, err := c.FindGraphAnnotations(ctx, service, 0, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range annotations {
+			if a.ID == annotationID {
+				return a, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("graph annotation %s is not found in services %s", annotationID, strings.Join(services, ", "))
+}
+
+func (r *graphAnnotation) convertToParam(ctx context.Context, properties map[string]any) (*graphAnnotationParam, error) {
+	var d dproxy.Drain
+	in, tracker := dproxy.Track(properties)
+
+	// From and To are zero if they are omitted. create and update fill them.
+	param := &mackerel.GraphAnnotation{
+		Title:       d.String(in.M("Title")),
+		Description: d.String(dproxy.Default(in.M("Description"), "")),
+		From:        mackerel.Timestamp(d.Int64(dproxy.Default(in.M("From"), 0))),
+		To:          mackerel.Timestamp(d.Int64(dproxy.Default(in.M("To"), 0))),
+	}
+
+	service, err := r.Function.parseServiceID(ctx, d.String(in.M("Service")))
+	d.Put(err)
+	param.Service = service
+
+	if roles, err := in.M("Roles").ProxySet().StringArray(); err == nil {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			serviceName, roleName, err := r.Function.parseRoleID(ctx, role)
+			if err != nil {
+				d.Put(err)
+				continue
+			}
+			if serviceName != service {
+				d.Put(fmt.Errorf("role %s does not belong to service %s", role, service))
+				continue
+			}
+			names = append(names, roleName)
+		}
+		param.Roles = names
+	} else if !dproxy.IsErrorCode(err, dproxy.ErrorCodeNotFound) {
+		d.Put(err)
+	}
+
+	periodOnUpdate := d.String(dproxy.Default(in.M("PeriodOnUpdate"), periodOnUpdateKeep))
+	switch periodOnUpdate {
+	case periodOnUpdateKeep, periodOnUpdateNow:
+	default:
+		d.Put(fmt.Errorf("invalid period on update: %s", periodOnUpdate))
+	}
+
+	checkUnknownProperties(&d, r.Event, tracker)
+	if err := d.CombineErrors(); err != nil {
+		return nil, err
+	}
+	return &graphAnnotationParam{
+		GraphAnnotation: param,
+		PeriodOnUpdate:  periodOnUpdate,
+	}, nil
+}
+
+func (r *graphAnnotation) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	physicalResourceID = r.Event.PhysicalResourceID
+	id, err := r.Function.parseGraphAnnotationID(ctx, physicalResourceID)
+	if err != nil {
+		log.Printf("failed to parse %q as graph annotation id: %s", physicalResourceID, err)
+		err = nil // ignore it
+		return
+	}
+	_, err = c.DeleteGraphAnnotation(ctx, id)
+	var merr mackerel.Error
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the graph annotation %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,383 @@
+package cfn
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestCreateGraphAnnotation(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		now: func() time.Time { return time.Unix(1484000000, 0) },
+		client: &fakeMackerelClient{
+			createGraphAnnotation: func(ctx context.Context, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+				want := &mackerel.GraphAnnotation{
+					Title:       "deploy",
+					Description: "foobar is updated",
+					From:        1484000000,
+					To:          1484000000,
+					Service:     "foo",
+					Roles:       []string{"bar", "baz"},
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("graph annotation differs: (-got +want)\n%s", diff)
+				}
+				ret := *param
+				ret.ID = "2bj..."
+				return &ret, nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id123",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::GraphAnnotation",
+		LogicalResourceID: "GraphAnnotation",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service":     "mkr:test-org:service:foo",
+			"Roles":       []any{"mkr:test-org:role:foo:bar", "mkr:test-org:role:foo:baz"},
+			"Title":       "deploy",
+			"Description": "foobar is updated",
+		},
+	}
+	id, data, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-annotation:2bj..." {
+		t.Errorf("unexpected graph annotation id: want %s, got %s", "mkr:test-org:graph-annotation:2bj...", id)
+	}
+	if diff := cmp.Diff(data, map[string]any{"From": int64(1484000000), "To": int64(1484000000)}); diff != "" {
+		t.Errorf("data differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestCreateGraphAnnotation_roleInOtherService(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createGraphAnnotation: func(ctx context.Context, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+				t.Error("the graph annotation should not be created")
+				return nil, nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id123",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::GraphAnnotation",
+		LogicalResourceID: "GraphAnnotation",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service": "mkr:test-org:service:foo",
+			"Roles":   []any{"mkr:test-org:role:hoge:bar"},
+			"Title":   "deploy",
+		},
+	}
+	_, _, err := f.Handle(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "does not belong to service foo") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateGraphAnnotation(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			updateGraphAnnotation: func(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+				if annotationID != "2bj..." {
+					t.Errorf("unexpected annotation id: want %s, got %s", "2bj...", annotationID)
+				}
+				want := &mackerel.GraphAnnotation{
+					Title:   "deploy",
+					From:    1484000000,
+					To:      1484000030,
+					Service: "foo",
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("graph annotation differs: (-got +want)\n%s", diff)
+				}
+				ret := *param
+				ret.ID = annotationID
+				return &ret, nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::GraphAnnotation",
+		LogicalResourceID:  "GraphAnnotation",
+		PhysicalResourceID: "mkr:test-org:graph-annotation:2bj...",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service": "mkr:test-org:service:foo",
+			"Title":   "deploy",
+			"From":    "1484000000",
+			"To":      "1484000030",
+		},
+		OldResourceProperties: map[string]any{
+			"Service": "mkr:test-org:service:foo",
+			"Title":   "deploy",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-annotation:2bj..." {
+		t.Errorf("unexpected graph annotation id: want %s, got %s", "mkr:test-org:graph-annotation:2bj...", id)
+	}
+}
+
+func TestUpdateGraphAnnotation_keepPeriod(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		now: func() time.Time { return time.Unix(1484001000, 0) },
+		client: &fakeMackerelClient{
+			findGraphAnnotations: func(ctx context.Context, service string, from, to int64) ([]*mackerel.GraphAnnotation, error) {
+				if service != "foo" {
+					t.Errorf("unexpected service: want %s, got %s", "foo", service)
+				}
+				if from > 1484000000 || to < 1484000000 {
+					t.Errorf("unexpected period: %d-%d", from, to)
+				}
+				return []*mackerel.GraphAnnotation{
+					{ID: "other", Title: "other", From: 1484000500, To: 1484000500, Service: "foo"},
+					{ID: "2bj...", Title: "deploy", From: 1484000000, To: 1484000000, Service: "foo"},
+				}, nil
+			},
+			updateGraphAnnotation: func(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+				want := &mackerel.GraphAnnotation{
+					Title:       "deploy",
+					Description: "updated",
+					From:        1484000000,
+					To:          1484000000,
+					Service:     "foo",
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("graph annotation differs: (-got +want)\n%s", diff)
+				}
+				ret := *param
+				ret.ID = annotationID
+				return &ret, nil
+			},
+		},
+	}
+
+	// the update only changes the description, the period is not moved to the current time.
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::GraphAnnotation",
+		LogicalResourceID:  "GraphAnnotation",
+		PhysicalResourceID: "mkr:test-org:graph-annotation:2bj...",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service":     "mkr:test-org:service:foo",
+			"Title":       "deploy",
+			"Description": "updated",
+		},
+		OldResourceProperties: map[string]any{
+			"Service": "mkr:test-org:service:foo",
+			"Title":   "deploy",
+		},
+	}
+	_, data, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(data, map[string]any{"From": int64(1484000000), "To": int64(1484000000)}); diff != "" {
+		t.Errorf("data differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDeleteGraphAnnotation(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			deleteGraphAnnotation: func(ctx context.Context, annotationID string) (*mackerel.GraphAnnotation, error) {
+				if annotationID != "2bj..." {
+					t.Errorf("unexpected annotation id: want %s, got %s", "2bj...", annotationID)
+				}
+				return nil, mkrError{
+					statusCode: 404,
+					message:    "Graph annotation not found",
+				}
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestDelete,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::GraphAnnotation",
+		LogicalResourceID:  "GraphAnnotation",
+		PhysicalResourceID: "mkr:test-org:graph-annotation:2bj...",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-annotation:2bj..." {
+		t.Errorf("unexpected graph annotation id: want %s, got %s", "mkr:test-org:graph-annotation:2bj...", id)
+	}
+}
+
+func TestUpdateGraphAnnotation_findPeriod(t *testing.T) {
+	tests := []struct {
+		name string
+		at   int64
+		old  map[string]any
+		new  map[string]any
+	}{
+		{
+			name: "the period in the future",
+			at:   1484100000,
+			old: map[string]any{
+				"Service": "mkr:test-org:service:foo",
+				"Title":   "deploy",
+				"From":    1484100000,
+				"To":      1484100000,
+			},
+			new: map[string]any{
+				"Service": "mkr:test-org:service:foo",
+				"Title":   "deploy",
+			},
+		},
+		{
+			// CloudFormation rolls back the failed update that has moved the annotation to service bar.
+			name: "rollback of the service",
+			at:   1484000000,
+			old: map[string]any{
+				"Service": "mkr:test-org:service:bar",
+				"Title":   "deploy",
+			},
+			new: map[string]any{
+				"Service": "mkr:test-org:service:foo",
+				"Title":   "deploy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Function{
+				org: &mackerel.Org{
+					Name: "test-org",
+				},
+				now: func() time.Time { return time.Unix(1484001000, 0) },
+				client: &fakeMackerelClient{
+					findGraphAnnotations: func(ctx context.Context, service string, from, to int64) ([]*mackerel.GraphAnnotation, error) {
+						// the annotation is still in service foo.
+						if service != "foo" || from > tt.at || to < tt.at {
+							return []*mackerel.GraphAnnotation{}, nil
+						}
+						return []*mackerel.GraphAnnotation{
+							{ID: "2bj...", Title: "deploy", From: mackerel.Timestamp(tt.at), To: mackerel.Timestamp(tt.at), Service: "foo"},
+						}, nil
+					},
+					updateGraphAnnotation: func(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+						ret := *param
+						ret.ID = annotationID
+						return &ret, nil
+					},
+				},
+			}
+			event := cfn.Event{
+				RequestType:           cfn.RequestUpdate,
+				RequestID:             "request-id123",
+				ResourceType:          "Custom::GraphAnnotation",
+				LogicalResourceID:     "GraphAnnotation",
+				PhysicalResourceID:    "mkr:test-org:graph-annotation:2bj...",
+				StackID:               "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+				ResourceProperties:    tt.new,
+				OldResourceProperties: tt.old,
+			}
+			_, data, err := f.Handle(context.Background(), event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(data, map[string]any{"From": tt.at, "To": tt.at}); diff != "" {
+				t.Errorf("data differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateGraphAnnotation_periodOnUpdateNow(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		now: func() time.Time { return time.Unix(1484001000, 0) },
+		client: &fakeMackerelClient{
+			updateGraphAnnotation: func(ctx context.Context, annotationID string, param *mackerel.GraphAnnotation) (*mackerel.GraphAnnotation, error) {
+				want := &mackerel.GraphAnnotation{
+					Title:   "deploy",
+					From:    1484001000,
+					To:      1484001000,
+					Service: "foo",
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("graph annotation differs: (-got +want)\n%s", diff)
+				}
+				ret := *param
+				ret.ID = annotationID
+				return &ret, nil
+			},
+		},
+	}
+
+	// the annotation moves to the time of the update, as a deploy marker.
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResourceType:       "Custom::GraphAnnotation",
+		LogicalResourceID:  "GraphAnnotation",
+		PhysicalResourceID: "mkr:test-org:graph-annotation:2bj...",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Service":        "mkr:test-org:service:foo",
+			"Title":          "deploy",
+			"PeriodOnUpdate": "now",
+		},
+		OldResourceProperties: map[string]any{
+			"Service":        "mkr:test-org:service:foo",
+			"Title":          "deploy",
+			"PeriodOnUpdate": "now",
+		},
+	}
+	_, data, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(data, map[string]any{"From": int64(1484001000), "To": int64(1484001000)}); diff != "" {
+		t.Errorf("data differs: (-got +want)\n%s", diff)
+	}
+}
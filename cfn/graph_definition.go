@@ -0,0 +1,108 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+type graphDefinition struct {
+	Function *Function
+	Event    cfn.Event
+}
+
+func (g *graphDefinition) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := g.Function.getclient()
+	param, err := g.convertToParam(ctx, g.Event.ResourceProperties)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.CreateGraphDefinitions(ctx, []*mackerel.GraphDefinition{param}); err != nil {
+		return "", nil, err
+	}
+
+	id, err := g.Function.buildGraphDefinitionID(ctx, param.Name)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, map[string]any{
+		"Name": param.Name,
+	}, nil
+}
+
+func (g *graphDefinition) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	var d dproxy.Drain
+	in := dproxy.New(g.Event.ResourceProperties)
+	old := dproxy.New(g.Event.OldResourceProperties)
+	name := d.String(in.M("Name"))
+	oldName := d.String(old.M("Name"))
+	if err := d.CombineErrors(); err != nil {
+		return g.Event.PhysicalResourceID, nil, err
+	}
+
+	if name != oldName {
+		// need to create a new graph definition.
+		// CloudFormation will delete the old one.
+		return g.create(ctx)
+	}
+
+	// the graph definition with same name is overwritten.
+	c := g.Function.getclient()
+	param, err := g.convertToParam(ctx, g.Event.ResourceProperties)
+	if err != nil {
+		return g.Event.PhysicalResourceID, nil, err
+	}
+	if err := c.CreateGraphDefinitions(ctx, []*mackerel.GraphDefinition{param}); err != nil {
+		return g.Event.PhysicalResourceID, nil, err
+	}
+	return g.Event.PhysicalResourceID, map[string]any{
+		"Name": param.Name,
+	}, nil
+}
+
+func (g *graphDefinition) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.GraphDefinition, error) {
+	var d dproxy.Drain
+	in, tracker := dproxy.Track(properties)
+	param := &mackerel.GraphDefinition{
+		Name:        d.String(in.M("Name")),
+		DisplayName: d.String(dproxy.Default(in.M("DisplayName"), "")),
+		Unit:        d.String(dproxy.Default(in.M("Unit"), "float")),
+		Metrics:     []*mackerel.GraphDefinitionMetric{},
+	}
+	for _, m := range d.ProxyArray(in.M("Metrics").ProxySet()) {
+		param.Metrics = append(param.Metrics, &mackerel.GraphDefinitionMetric{
+			Name:        d.String(m.M("Name")),
+			DisplayName: d.String(dproxy.Default(m.M("DisplayName"), "")),
+			IsStacked:   d.Bool(dproxy.Default(m.M("IsStacked"), false)),
+		})
+	}
+	checkUnknownProperties(&d, g.Event, tracker)
+	if err := d.CombineErrors(); err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+func (g *graphDefinition) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	physicalResourceID = g.Event.PhysicalResourceID
+	name, err := g.Function.parseGraphDefinitionID(ctx, physicalResourceID)
+	if err != nil {
+		log.Printf("failed to parse %q as graph definition id: %s", physicalResourceID, err)
+		err = nil
+		return
+	}
+
+	c := g.Function.getclient()
+	err = c.DeleteGraphDefinition(ctx, name)
+	var merr mackerel.Error
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the graph definition %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,182 @@
+package cfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestCreateGraphDefinition(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createGraphDefinitions: func(ctx context.Context, defs []*mackerel.GraphDefinition) error {
+				want := []*mackerel.GraphDefinition{
+					{
+						Name:        "custom.myapp.requests",
+						DisplayName: "MyApp Requests",
+						Unit:        "integer",
+						Metrics: []*mackerel.GraphDefinitionMetric{
+							{
+								Name:        "custom.myapp.requests.success",
+								DisplayName: "Success",
+								IsStacked:   true,
+							},
+							{
+								Name: "custom.myapp.requests.error",
+							},
+						},
+					},
+				}
+				if diff := cmp.Diff(defs, want); diff != "" {
+					t.Errorf("graph definition differs: (-got +want)\n%s", diff)
+				}
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id123",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::GraphDefinition",
+		LogicalResourceID: "GraphDefinition",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name":        "custom.myapp.requests",
+			"DisplayName": "MyApp Requests",
+			"Unit":        "integer",
+			"Metrics": []any{
+				map[string]any{
+					"Name":        "custom.myapp.requests.success",
+					"DisplayName": "Success",
+					"IsStacked":   true,
+				},
+				map[string]any{
+					"Name": "custom.myapp.requests.error",
+				},
+			},
+		},
+	}
+	id, data, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-def:custom.myapp.requests" {
+		t.Errorf("unexpected graph definition id: want %s, got %s", "mkr:test-org:graph-def:custom.myapp.requests", id)
+	}
+	name, _ := data["Name"].(string)
+	if name != "custom.myapp.requests" {
+		t.Errorf("unexpected name: want %s, got %s", "custom.myapp.requests", name)
+	}
+}
+
+func TestUpdateGraphDefinition(t *testing.T) {
+	var updated bool
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createGraphDefinitions: func(ctx context.Context, defs []*mackerel.GraphDefinition) error {
+				updated = true
+				want := []*mackerel.GraphDefinition{
+					{
+						Name: "custom.myapp.requests",
+						Unit: "float",
+						Metrics: []*mackerel.GraphDefinitionMetric{
+							{
+								Name: "custom.myapp.requests.*",
+							},
+						},
+					},
+				}
+				if diff := cmp.Diff(defs, want); diff != "" {
+					t.Errorf("graph definition differs: (-got +want)\n%s", diff)
+				}
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::GraphDefinition",
+		LogicalResourceID:  "GraphDefinition",
+		PhysicalResourceID: "mkr:test-org:graph-def:custom.myapp.requests",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Name": "custom.myapp.requests",
+			"Metrics": []any{
+				map[string]any{
+					"Name": "custom.myapp.requests.*",
+				},
+			},
+		},
+		OldResourceProperties: map[string]any{
+			"Name": "custom.myapp.requests",
+			"Unit": "integer",
+			"Metrics": []any{
+				map[string]any{
+					"Name": "custom.myapp.requests.success",
+				},
+			},
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-def:custom.myapp.requests" {
+		t.Errorf("unexpected graph definition id: want %s, got %s", "mkr:test-org:graph-def:custom.myapp.requests", id)
+	}
+	if !updated {
+		t.Error("want updated, but not")
+	}
+}
+
+func TestDeleteGraphDefinition(t *testing.T) {
+	var deleted bool
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			deleteGraphDefinition: func(ctx context.Context, name string) error {
+				if name != "custom.myapp.requests" {
+					t.Errorf("unexpected graph definition name: want %s, got %s", "custom.myapp.requests", name)
+				}
+				deleted = true
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestDelete,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::GraphDefinition",
+		LogicalResourceID:  "GraphDefinition",
+		PhysicalResourceID: "mkr:test-org:graph-def:custom.myapp.requests",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:graph-def:custom.myapp.requests" {
+		t.Errorf("unexpected graph definition id: want %s, got %s", "mkr:test-org:graph-def:custom.myapp.requests", id)
+	}
+	if !deleted {
+		t.Error("want deleted, but not")
+	}
+}
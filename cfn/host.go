@@ -3,8 +3,11 @@ package cfn
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"reflect"
+	"slices"
 
 	"github.com/aws/aws-lambda-go/cfn"
 	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
@@ -16,13 +19,35 @@ type host struct {
 	Event    cfn.Event
 }
 
+// hostParam is the properties of Mackerel::Host.
+type hostParam struct {
+	*mackerel.CreateHostParam
+
+	// Status is the status of the host.
+	// It is empty if the stack doesn't manage the status.
+	Status string
+
+	// RolesMode is how the roles of the host are updated.
+	RolesMode string
+}
+
+// The modes of updating the roles of hosts.
+const (
+	// rolesModeReplace replaces all the roles of the host with the Roles property.
+	rolesModeReplace = "replace"
+
+	// rolesModeMerge adds and removes only the roles that the stack manages,
+	// so the roles added by mackerel-agent or operators are kept.
+	rolesModeMerge = "merge"
+)
+
 func (h *host) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	c := h.Function.getclient()
 	param, err := h.convertToParam(ctx, h.Event.ResourceProperties)
 	if err != nil {
 		return "", nil, err
 	}
-	hostID, err := c.CreateHost(ctx, param)
+	hostID, err := c.CreateHost(ctx, param.CreateHostParam)
 	if err != nil {
 		return "", nil, err
 	}
@@ -36,8 +61,18 @@ func (h *host) create(ctx context.Context) (physicalResourceID string, data map[
 	if err != nil {
 		return "", nil, err
 	}
+	if param.Status != "" {
+		if err := c.UpdateHostStatus(ctx, hostID, param.Status); err != nil {
+			return id, nil, err
+		}
+	}
+	status, err := h.currentStatus(ctx, hostID)
+	if err != nil {
+		return id, nil, err
+	}
 	return id, map[string]any{
-		"Name": param.Name,
+		"Name":   param.Name,
+		"Status": status,
 	}, nil
 }
 
@@ -51,26 +86,148 @@ func (h *host) update(ctx context.Context) (physicalResourceID string, data map[
 	if err != nil {
 		return h.Event.PhysicalResourceID, nil, err
 	}
-	_, err = c.UpdateHost(ctx, id, (*mackerel.UpdateHostParam)(param))
-	if err != nil {
+	if err := h.Function.checkHostOwner(ctx, h.Event, id); err != nil {
+		return h.Event.PhysicalResourceID, nil, err
+	}
+	if param.RolesMode == rolesModeMerge {
+		if err := h.updateWithMergingRoles(ctx, id, param); err != nil {
+			return h.Event.PhysicalResourceID, nil, err
+		}
+	} else {
+		_, err = c.UpdateHost(ctx, id, (*mackerel.UpdateHostParam)(param.CreateHostParam))
+		if err != nil {
+			return h.Event.PhysicalResourceID, nil, err
+		}
+	}
+	if param.Status != "" {
+		// apply the status even if it is not changed, because operators or agents may change it.
+		if err := c.UpdateHostStatus(ctx, id, param.Status); err != nil {
+			return h.Event.PhysicalResourceID, nil, err
+		}
+	}
+
+	// the owner may be changed by ForceOwnership.
+	meta := getmetadata(h.Event)
+	if err := c.PutHostMetaData(ctx, id, "cloudformation", meta); err != nil {
 		return h.Event.PhysicalResourceID, nil, err
 	}
 
+	status, err := h.currentStatus(ctx, id)
+	if err != nil {
+		return h.Event.PhysicalResourceID, nil, err
+	}
 	return h.Event.PhysicalResourceID, map[string]any{
-		"Name": param.Name,
+		"Name":   param.Name,
+		"Status": status,
 	}, nil
 }
 
-func (h *host) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.CreateHostParam, error) {
+// currentStatus returns the status of the host for the Status attribute.
+// It is read back from Mackerel, because the status is decided by Mackerel if the Status property is omitted.
+func (h *host) currentStatus(ctx context.Context, hostID string) (string, error) {
+	c := h.Function.getclient()
+	current, err := c.FindHost(ctx, hostID)
+	if err != nil {
+		return "", err
+	}
+	return current.Status, nil
+}
+
+// updateWithMergingRoles updates the host without overwriting the roles that the stack doesn't manage.
+func (h *host) updateWithMergingRoles(ctx context.Context, hostID string, param *hostParam) error {
+	c := h.Function.getclient()
+	oldParam, err := h.convertToParam(ctx, h.Event.OldResourceProperties)
+	if err != nil {
+		return err
+	}
+
+	// read the current roles before any update, because UpdateHost replaces all the roles.
+	current, err := c.FindHost(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	roleFullnames := mergeRoles(current.Roles.Fullnames(), oldParam.RoleFullnames, param.RoleFullnames)
+
+	// UpdateHost is skipped if only the roles are changed.
+	updateParam := mackerel.UpdateHostParam(*param.CreateHostParam)
+	updateParam.RoleFullnames = nil
+	oldUpdateParam := mackerel.UpdateHostParam(*oldParam.CreateHostParam)
+	oldUpdateParam.RoleFullnames = nil
+	if !reflect.DeepEqual(updateParam, oldUpdateParam) {
+		updateParam.RoleFullnames = roleFullnames
+		if _, err := c.UpdateHost(ctx, hostID, &updateParam); err != nil {
+			return err
+		}
+		if len(roleFullnames) > 0 {
+			return nil
+		}
+		// the empty roles are omitted from the request, so they are cleared explicitly.
+	}
+	return c.UpdateHostRoleFullnames(ctx, hostID, roleFullnames)
+}
+
+// mergeRoles removes the roles which the stack no longer manages from current,
+// and adds the roles which the stack manages.
+func mergeRoles(current, oldManaged, newManaged []string) []string {
+	var ret []string
+	for _, role := range current {
+		if slices.Contains(oldManaged, role) && !slices.Contains(newManaged, role) {
+			continue
+		}
+		ret = append(ret, role)
+	}
+	for _, role := range newManaged {
+		if !slices.Contains(ret, role) {
+			ret = append(ret, role)
+		}
+	}
+	return ret
+}
+
+func (h *host) convertToParam(ctx context.Context, properties map[string]any) (*hostParam, error) {
 	var param mackerel.CreateHostParam
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 	param.Name = d.String(in.M("Name"))
+	param.DisplayName = d.String(dproxy.Default(in.M("DisplayName"), ""))
+	param.CustomIdentifier = d.String(dproxy.Default(in.M("CustomIdentifier"), ""))
+	param.Memo = d.String(dproxy.Default(in.M("Memo"), ""))
+	param.Interfaces = h.convertInterfaces(&d, dproxy.Default(in.M("Interfaces"), []any{}))
+	param.Checks = h.convertChecks(&d, dproxy.Default(in.M("Checks"), []any{}))
 	roles := d.Array(in.M("Roles"))
+	status := d.String(dproxy.Default(in.M("Status"), ""))
+	switch status {
+	case "", mackerel.HostStatusWorking, mackerel.HostStatusStandby, mackerel.HostStatusMaintenance, mackerel.HostStatusPoweroff:
+	default:
+		d.Put(fmt.Errorf("invalid host status: %s", status))
+	}
+	rolesMode := d.String(dproxy.Default(in.M("RolesMode"), rolesModeReplace))
+	switch rolesMode {
+	case rolesModeReplace, rolesModeMerge:
+	default:
+		d.Put(fmt.Errorf("invalid roles mode: %s", rolesMode))
+	}
+	checkUnknownProperties(&d, h.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
 
+	roleFullnames, err := h.convertRoles(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+	param.RoleFullnames = roleFullnames
+
+	return &hostParam{
+		CreateHostParam: &param,
+		Status:          status,
+		RolesMode:       rolesMode,
+	}, nil
+}
+
+// convertRoles converts the ids of roles into the full names of roles.
+func (h *host) convertRoles(ctx context.Context, roles []any) ([]string, error) {
+	var roleFullnames []string
 	for _, r := range roles {
 		id, err := dproxy.New(r).String()
 		if err != nil {
@@ -80,10 +237,33 @@ func (h *host) convertToParam(ctx context.Context, properties map[string]any) (*
 		if err != nil {
 			return nil, err
 		}
-		param.RoleFullnames = append(param.RoleFullnames, serviceName+":"+roleName)
+		roleFullnames = append(roleFullnames, serviceName+":"+roleName)
 	}
+	return roleFullnames, nil
+}
+
+func (h *host) convertInterfaces(d *dproxy.Drain, properties dproxy.Proxy) []mackerel.Interface {
+	var interfaces []mackerel.Interface
+	for _, item := range d.ProxyArray(properties.ProxySet()) {
+		interfaces = append(interfaces, mackerel.Interface{
+			Name:          d.String(item.M("Name")),
+			IPv4Addresses: d.StringArray(dproxy.Default(item.M("Ipv4Addresses"), []any{}).ProxySet()),
+			IPv6Addresses: d.StringArray(dproxy.Default(item.M("Ipv6Addresses"), []any{}).ProxySet()),
+			MacAddress:    d.String(dproxy.Default(item.M("MacAddress"), "")),
+		})
+	}
+	return interfaces
+}
 
-	return &param, nil
+func (h *host) convertChecks(d *dproxy.Drain, properties dproxy.Proxy) []mackerel.CheckConfig {
+	var checks []mackerel.CheckConfig
+	for _, item := range d.ProxyArray(properties.ProxySet()) {
+		checks = append(checks, mackerel.CheckConfig{
+			Name: d.String(item.M("Name")),
+			Memo: d.String(dproxy.Default(item.M("Memo"), "")),
+		})
+	}
+	return checks
 }
 
 func (h *host) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
@@ -95,6 +275,10 @@ func (h *host) delete(ctx context.Context) (physicalResourceID string, data map[
 		return
 	}
 
+	if err = h.Function.checkHostOwner(ctx, h.Event, id); err != nil {
+		return
+	}
+
 	c := h.Function.getclient()
 	err = c.RetireHost(ctx, id)
 	var merr mackerel.Error
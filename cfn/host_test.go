@@ -23,6 +23,9 @@ func TestCreateHost(t *testing.T) {
 					}
 					return "3yAYEDLXKL5", nil
 				},
+				findHost: func(ctx context.Context, hostID string) (*mackerel.Host, error) {
+					return &mackerel.Host{ID: hostID, Status: "working"}, nil
+				},
 				putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
 					if namespace != "cloudformation" {
 						t.Errorf("unexpected namespace: want cloudformation, got %s", namespace)
@@ -66,6 +69,368 @@ func TestCreateHost(t *testing.T) {
 	if param["Name"].(string) != "host-foobar" {
 		t.Errorf("unexpected name, want %s, got %s", "host-foobar", param["Name"].(string))
 	}
+
+	// the status is reported even if the Status property is omitted.
+	if param["Status"].(string) != "working" {
+		t.Errorf("unexpected status, want %s, got %s", "working", param["Status"].(string))
+	}
+}
+
+func TestCreateHost_fullDefinition(t *testing.T) {
+	h := &host{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createHost: func(ctx context.Context, param *mackerel.CreateHostParam) (string, error) {
+					want := &mackerel.CreateHostParam{
+						Name:             "router-01",
+						DisplayName:      "Core Router",
+						CustomIdentifier: "router-01.example.com",
+						Memo:             "the core router of the office",
+						Interfaces: []mackerel.Interface{
+							{
+								Name:          "eth0",
+								IPv4Addresses: []string{"192.0.2.1"},
+								IPv6Addresses: []string{"2001:db8::1"},
+								MacAddress:    "00:00:5e:00:53:01",
+							},
+							{
+								Name:          "eth1",
+								IPv4Addresses: []string{},
+								IPv6Addresses: []string{},
+							},
+						},
+						RoleFullnames: []string{"awesome-service:role-hogehoge"},
+						Checks: []mackerel.CheckConfig{
+							{Name: "ping", Memo: "the router is reachable"},
+						},
+					}
+					if diff := cmp.Diff(param, want); diff != "" {
+						t.Errorf("host differs: (-got +want)\n%s", diff)
+					}
+					return "3yAYEDLXKL5", nil
+				},
+				findHost: func(ctx context.Context, hostID string) (*mackerel.Host, error) {
+					return &mackerel.Host{ID: hostID, Status: "working"}, nil
+				},
+				putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			ResourceType:      "Custom:Host",
+			LogicalResourceID: "Host",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name":             "router-01",
+				"DisplayName":      "Core Router",
+				"CustomIdentifier": "router-01.example.com",
+				"Memo":             "the core router of the office",
+				"Roles":            []any{"mkr:test-org:role:awesome-service:role-hogehoge"},
+				"Interfaces": []any{
+					map[string]any{
+						"Name":          "eth0",
+						"Ipv4Addresses": []any{"192.0.2.1"},
+						"Ipv6Addresses": []any{"2001:db8::1"},
+						"MacAddress":    "00:00:5e:00:53:01",
+					},
+					map[string]any{
+						"Name": "eth1",
+					},
+				},
+				"Checks": []any{
+					map[string]any{
+						"Name": "ping",
+						"Memo": "the router is reachable",
+					},
+				},
+			},
+		},
+	}
+	id, _, err := h.create(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:host:3yAYEDLXKL5" {
+		t.Errorf("unexpected host id: want %s, got %s", "mkr:test-org:host:3yAYEDLXKL5", id)
+	}
+}
+
+func TestCreateHost_status(t *testing.T) {
+	var status string
+	h := &host{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createHost: func(ctx context.Context, param *mackerel.CreateHostParam) (string, error) {
+					return "3yAYEDLXKL5", nil
+				},
+				findHost: func(ctx context.Context, hostID string) (*mackerel.Host, error) {
+					return &mackerel.Host{ID: hostID, Status: status}, nil
+				},
+				putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+					return nil
+				},
+				updateHostStatus: func(ctx context.Context, hostID, s string) error {
+					if hostID != "3yAYEDLXKL5" {
+						t.Errorf("unexpected host id, want %s, got %s", "3yAYEDLXKL5", hostID)
+					}
+					status = s
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			ResourceType:      "Custom:Host",
+			LogicalResourceID: "Host",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name":   "host-foobar",
+				"Roles":  []any{"mkr:test-org:role:awesome-service:role-hogehoge"},
+				"Status": "standby",
+			},
+		},
+	}
+	_, param, err := h.create(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "standby" {
+		t.Errorf("unexpected status: want %s, got %s", "standby", status)
+	}
+	if param["Status"].(string) != "standby" {
+		t.Errorf("unexpected status, want %s, got %s", "standby", param["Status"].(string))
+	}
+}
+
+func TestUpdateHost_status(t *testing.T) {
+	var status string
+	h := &host{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getHostMetaData: func(ctx context.Context, hostID, namespace string, v any) (*mackerel.HostMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.HostMetaMetaData{}, nil
+				},
+				updateHost: func(ctx context.Context, hostID string, param *mackerel.UpdateHostParam) (string, error) {
+					return hostID, nil
+				},
+				findHost: func(ctx context.Context, hostID string) (*mackerel.Host, error) {
+					return &mackerel.Host{ID: hostID, Status: status}, nil
+				},
+				putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+					return nil
+				},
+				updateHostStatus: func(ctx context.Context, hostID, s string) error {
+					status = s
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom:Host",
+			LogicalResourceID:  "Host",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:host:3yAYEDLXKL5",
+			OldResourceProperties: map[string]any{
+				"Name":   "host-foobar",
+				"Roles":  []any{},
+				"Status": "working",
+			},
+			ResourceProperties: map[string]any{
+				"Name":   "host-foobar",
+				"Roles":  []any{},
+				"Status": "maintenance",
+			},
+		},
+	}
+	_, param, err := h.update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "maintenance" {
+		t.Errorf("unexpected status: want %s, got %s", "maintenance", status)
+	}
+	if param["Status"].(string) != "maintenance" {
+		t.Errorf("unexpected status, want %s, got %s", "maintenance", param["Status"].(string))
+	}
+}
+
+func TestUpdateHost_mergeRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldMemo string
+		newMemo string
+		calls   []string
+	}{
+		{
+			name:    "roles only",
+			oldMemo: "memo",
+			newMemo: "memo",
+			calls:   []string{"FindHost", "UpdateHostRoleFullnames", "FindHost"},
+		},
+		{
+			name:    "memo and roles",
+			oldMemo: "old memo",
+			newMemo: "new memo",
+			calls:   []string{"FindHost", "UpdateHost", "FindHost"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls, roleFullnames []string
+			h := &host{
+				Function: &Function{
+					org: &mackerel.Org{
+						Name: "test-org",
+					},
+					client: &fakeMackerelClient{
+						getHostMetaData: func(ctx context.Context, hostID, namespace string, v any) (*mackerel.HostMetaMetaData, error) {
+							v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+							return &mackerel.HostMetaMetaData{}, nil
+						},
+						findHost: func(ctx context.Context, hostID string) (*mackerel.Host, error) {
+							calls = append(calls, "FindHost")
+							return &mackerel.Host{
+								ID: hostID,
+								Roles: mackerel.Roles{
+									"awesome-service": {"role-old", "role-kept"},
+									"agent-service":   {"role-agent"},
+								},
+							}, nil
+						},
+						updateHost: func(ctx context.Context, hostID string, param *mackerel.UpdateHostParam) (string, error) {
+							calls = append(calls, "UpdateHost")
+							roleFullnames = param.RoleFullnames
+							if param.Memo != tt.newMemo {
+								t.Errorf("unexpected memo: want %q, got %q", tt.newMemo, param.Memo)
+							}
+							return hostID, nil
+						},
+						updateHostRoleFullnames: func(ctx context.Context, hostID string, fullnames []string) error {
+							calls = append(calls, "UpdateHostRoleFullnames")
+							roleFullnames = fullnames
+							return nil
+						},
+						putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+							return nil
+						},
+					},
+				},
+				Event: cfn.Event{
+					RequestType:        cfn.RequestUpdate,
+					ResourceType:       "Custom:Host",
+					LogicalResourceID:  "Host",
+					StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					PhysicalResourceID: "mkr:test-org:host:3yAYEDLXKL5",
+					OldResourceProperties: map[string]any{
+						"Name": "host-foobar",
+						"Memo": tt.oldMemo,
+						"Roles": []any{
+							"mkr:test-org:role:awesome-service:role-old",
+							"mkr:test-org:role:awesome-service:role-kept",
+						},
+					},
+					ResourceProperties: map[string]any{
+						"Name":      "host-foobar",
+						"Memo":      tt.newMemo,
+						"RolesMode": "merge",
+						"Roles": []any{
+							"mkr:test-org:role:awesome-service:role-kept",
+							"mkr:test-org:role:awesome-service:role-new",
+						},
+					},
+				},
+			}
+			_, _, err := h.update(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the current roles are read before any update.
+			if diff := cmp.Diff(tt.calls, calls); diff != "" {
+				t.Errorf("unexpected calls (-want/+got):\n%s", diff)
+			}
+
+			// the role added by the agent is kept.
+			want := []string{"agent-service:role-agent", "awesome-service:role-kept", "awesome-service:role-new"}
+			if diff := cmp.Diff(want, roleFullnames); diff != "" {
+				t.Errorf("unexpected roles (-want/+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeRoles(t *testing.T) {
+	tests := []struct {
+		current    []string
+		oldManaged []string
+		newManaged []string
+		want       []string
+	}{
+		{
+			current:    nil,
+			oldManaged: nil,
+			newManaged: []string{"service:role"},
+			want:       []string{"service:role"},
+		},
+		{
+			current:    []string{"service:agent", "service:role"},
+			oldManaged: []string{"service:role"},
+			newManaged: nil,
+			want:       []string{"service:agent"},
+		},
+		{
+			// the role which the stack manages is kept even if an operator has removed it.
+			current:    []string{"service:agent"},
+			oldManaged: []string{"service:role"},
+			newManaged: []string{"service:role"},
+			want:       []string{"service:agent", "service:role"},
+		},
+	}
+	for _, tt := range tests {
+		got := mergeRoles(tt.current, tt.oldManaged, tt.newManaged)
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("mergeRoles(%v, %v, %v) (-want/+got):\n%s", tt.current, tt.oldManaged, tt.newManaged, diff)
+		}
+	}
+}
+
+func TestCreateHost_invalidStatus(t *testing.T) {
+	h := &host{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			ResourceType:      "Custom::Host",
+			LogicalResourceID: "Host",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name":   "host-foobar",
+				"Roles":  []any{},
+				"Status": "sleeping",
+			},
+		},
+	}
+	_, _, err := h.create(context.Background())
+	if err == nil || err.Error() != "invalid host status: sleeping" {
+		t.Errorf("unexpected error: %v", err)
+	}
 }
 
 func TestDeleteHost(t *testing.T) {
@@ -76,6 +441,11 @@ func TestDeleteHost(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getHostMetaData: func(ctx context.Context, hostID, namespace string, v any) (*mackerel.HostMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				retireHost: func(ctx context.Context, id string) error {
 					deleted = true
 					if id != "3yAYEDLXKL5" {
@@ -116,6 +486,11 @@ func TestDeleteHost_hotNotFound(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getHostMetaData: func(ctx context.Context, hostID, namespace string, v any) (*mackerel.HostMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				retireHost: func(ctx context.Context, id string) error {
 					deleted = true
 					if id != "3yAYEDLXKL5" {
@@ -0,0 +1,239 @@
+package cfn
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/mackereltest"
+)
+
+// testStack emulates CloudFormation to run the lifecycle of the resources against mackereltest.
+type testStack struct {
+	t          *testing.T
+	f          *Function
+	types      map[string]string
+	properties map[string]map[string]any
+	ids        map[string]string
+}
+
+func newTestStack(t *testing.T, srv *mackereltest.Server) *testStack {
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testStack{
+		t: t,
+		f: &Function{
+			APIKey:  mackereltest.DefaultAPIKey,
+			BaseURL: u,
+		},
+		types:      map[string]string{},
+		properties: map[string]map[string]any{},
+		ids:        map[string]string{},
+	}
+}
+
+func (s *testStack) event(requestType cfn.RequestType, logicalID string) cfn.Event {
+	return cfn.Event{
+		RequestType:       requestType,
+		RequestID:         "request-" + logicalID,
+		ResourceType:      "Custom::" + s.types[logicalID],
+		LogicalResourceID: logicalID,
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+	}
+}
+
+// apply creates or updates the resource, and returns its physical id.
+// If the resource is replaced, the old one is deleted as CloudFormation does.
+func (s *testStack) apply(logicalID, typ string, properties map[string]any) string {
+	s.t.Helper()
+	ctx := context.Background()
+	oldID, exists := s.ids[logicalID]
+	s.types[logicalID] = typ
+
+	var event cfn.Event
+	if exists {
+		event = s.event(cfn.RequestUpdate, logicalID)
+		event.PhysicalResourceID = oldID
+		event.OldResourceProperties = s.properties[logicalID]
+	} else {
+		event = s.event(cfn.RequestCreate, logicalID)
+	}
+	event.ResourceProperties = properties
+	id, _, err := s.f.Handle(ctx, event)
+	if err != nil {
+		s.t.Fatalf("failed to apply %s: %v", logicalID, err)
+	}
+
+	if exists && id != oldID {
+		event := s.event(cfn.RequestDelete, logicalID)
+		event.PhysicalResourceID = oldID
+		event.ResourceProperties = s.properties[logicalID]
+		if _, _, err := s.f.Handle(ctx, event); err != nil {
+			s.t.Fatalf("failed to clean up %s: %v", logicalID, err)
+		}
+	}
+	s.ids[logicalID] = id
+	s.properties[logicalID] = properties
+	return id
+}
+
+func (s *testStack) delete(logicalID string) {
+	s.t.Helper()
+	event := s.event(cfn.RequestDelete, logicalID)
+	event.PhysicalResourceID = s.ids[logicalID]
+	event.ResourceProperties = s.properties[logicalID]
+	if _, _, err := s.f.Handle(context.Background(), event); err != nil {
+		s.t.Fatalf("failed to delete %s: %v", logicalID, err)
+	}
+	delete(s.ids, logicalID)
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	srv := mackereltest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	s := newTestStack(t, srv)
+
+	// create the stack.
+	serviceID := s.apply("Service", "Service", map[string]any{
+		"Name": "awesome-service",
+	})
+	roleID := s.apply("Role", "Role", map[string]any{
+		"Service": serviceID,
+		"Name":    "app",
+	})
+	hostID := s.apply("Host", "Host", map[string]any{
+		"Name":  "host",
+		"Roles": []any{roleID},
+	})
+	monitorProps := map[string]any{
+		"Type":   "connectivity",
+		"Name":   "connectivity",
+		"Scopes": []any{roleID},
+	}
+	monitorID := s.apply("Monitor", "Monitor", monitorProps)
+	channelID := s.apply("Channel", "NotificationChannel", map[string]any{
+		"Type":   "email",
+		"Name":   "email",
+		"Emails": []any{"alice@example.com"},
+		"Events": []any{"alert"},
+	})
+	s.apply("Group", "NotificationGroup", map[string]any{
+		"Name":            "group",
+		"ChildChannelIds": []any{channelID},
+		"Monitors": []any{
+			map[string]any{"Id": monitorID},
+		},
+	})
+	s.apply("Dashboard", "Dashboard", map[string]any{
+		"Title":   "dashboard",
+		"UrlPath": "awesome-dashboard",
+		"Widgets": []any{
+			map[string]any{
+				"Type":     "markdown",
+				"Title":    "markdown",
+				"Markdown": "# hello",
+				"Layout": map[string]any{
+					"X": 0, "Y": 0, "Width": 8, "Height": 6,
+				},
+			},
+		},
+	})
+
+	services, err := c.FindServices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Name != "awesome-service" || len(services[0].Roles) != 1 || services[0].Roles[0] != "app" {
+		t.Errorf("unexpected services: %v", services)
+	}
+
+	// update the resources in place.
+	monitorProps = map[string]any{
+		"Type":   "connectivity",
+		"Name":   "renamed",
+		"Scopes": []any{roleID},
+	}
+	if id := s.apply("Monitor", "Monitor", monitorProps); id != monitorID {
+		t.Errorf("the monitor is replaced: %s -> %s", monitorID, id)
+	}
+	monitors, err := c.FindMonitors(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].MonitorName() != "renamed" {
+		t.Errorf("unexpected monitors: %v", monitors)
+	}
+
+	// renaming the role replaces it.
+	newRoleID := s.apply("Role", "Role", map[string]any{
+		"Service": serviceID,
+		"Name":    "web",
+	})
+	if newRoleID == roleID {
+		t.Errorf("the role is not replaced: %s", roleID)
+	}
+	if id := s.apply("Host", "Host", map[string]any{
+		"Name":  "host",
+		"Roles": []any{newRoleID},
+	}); id != hostID {
+		t.Errorf("the host is replaced: %s -> %s", hostID, id)
+	}
+	s.apply("Monitor", "Monitor", map[string]any{
+		"Type":   "connectivity",
+		"Name":   "renamed",
+		"Scopes": []any{newRoleID},
+	})
+	services, err = c.FindServices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Roles) != 1 || services[0].Roles[0] != "web" {
+		t.Errorf("unexpected services: %v", services)
+	}
+
+	// delete the stack in the reverse order of the dependencies.
+	for _, logicalID := range []string{"Dashboard", "Group", "Channel", "Monitor", "Host", "Role", "Service"} {
+		s.delete(logicalID)
+	}
+
+	services, err = c.FindServices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 0 {
+		t.Errorf("want no services, got %v", services)
+	}
+	monitors, err = c.FindMonitors(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 0 {
+		t.Errorf("want no monitors, got %v", monitors)
+	}
+	channels, err := c.FindNotificationChannels(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 0 {
+		t.Errorf("want no channels, got %v", channels)
+	}
+	groups, err := c.FindNotificationGroups(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("want no notification groups, got %v", groups)
+	}
+	dashboards, err := c.FindDashboards(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboards) != 0 {
+		t.Errorf("want no dashboards, got %v", dashboards)
+	}
+}
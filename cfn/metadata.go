@@ -0,0 +1,120 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// reservedMetadataNamespace is the namespace that the macro uses for tracking resources.
+const reservedMetadataNamespace = "cloudformation"
+
+type metadataResource struct {
+	Function *Function
+	Event    cfn.Event
+}
+
+func (r *metadataResource) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	target, namespace, doc, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := r.put(ctx, target, namespace, doc); err != nil {
+		return "", nil, err
+	}
+
+	id, err := r.Function.buildMetadataID(ctx, target, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, nil, nil
+}
+
+func (r *metadataResource) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	target, namespace, doc, err := r.convertToParam(ctx, r.Event.ResourceProperties)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	id, err := r.Function.buildMetadataID(ctx, target, namespace)
+	if err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+
+	if id != r.Event.PhysicalResourceID {
+		// need to write a new metadata.
+		// CloudFormation will delete the old one.
+		return r.create(ctx)
+	}
+
+	if err := r.put(ctx, target, namespace, doc); err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	return r.Event.PhysicalResourceID, nil, nil
+}
+
+func (r *metadataResource) convertToParam(ctx context.Context, properties map[string]any) (target metadataTarget, namespace string, doc any, err error) {
+	var d dproxy.Drain
+	in, tracker := dproxy.Track(properties)
+
+	target, err = r.Function.parseMetadataTarget(ctx, d.String(in.M("Target")))
+	d.Put(err)
+
+	namespace = d.String(in.M("Namespace"))
+	if namespace == reservedMetadataNamespace {
+		d.Put(fmt.Errorf("the namespace %q is reserved by cfn-mackerel-macro", reservedMetadataNamespace))
+	}
+
+	doc, err = in.M("Document").Value()
+	d.Put(err)
+
+	checkUnknownProperties(&d, r.Event, tracker)
+	if err := d.CombineErrors(); err != nil {
+		return metadataTarget{}, "", nil, err
+	}
+	return target, namespace, doc, nil
+}
+
+func (r *metadataResource) put(ctx context.Context, target metadataTarget, namespace string, doc any) error {
+	c := r.Function.getclient()
+	switch target.Type {
+	case "service":
+		return c.PutServiceMetaData(ctx, target.IDs[0], namespace, doc)
+	case "role":
+		return c.PutRoleMetaData(ctx, target.IDs[0], target.IDs[1], namespace, doc)
+	case "host":
+		return c.PutHostMetaData(ctx, target.IDs[0], namespace, doc)
+	}
+	return fmt.Errorf("unknown metadata target type: %s", target.Type)
+}
+
+func (r *metadataResource) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	c := r.Function.getclient()
+	physicalResourceID = r.Event.PhysicalResourceID
+	target, namespace, err := r.Function.parseMetadataID(ctx, physicalResourceID)
+	if err != nil {
+		log.Printf("failed to parse %q as metadata id: %s", physicalResourceID, err)
+		err = nil // ignore it
+		return
+	}
+
+	switch target.Type {
+	case "service":
+		err = c.DeleteServiceMetaData(ctx, target.IDs[0], namespace)
+	case "role":
+		err = c.DeleteRoleMetaData(ctx, target.IDs[0], target.IDs[1], namespace)
+	case "host":
+		err = c.DeleteHostMetaData(ctx, target.IDs[0], namespace)
+	}
+	var merr mackerel.Error
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the metadata %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+	}
+	return
+}
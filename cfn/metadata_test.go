@@ -0,0 +1,247 @@
+package cfn
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestCreateMetadata(t *testing.T) {
+	doc := map[string]any{
+		"runbook": "https://example.com/runbook",
+		"owners":  []any{"alice", "bob"},
+	}
+	var called bool
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+				t.Errorf("unexpected call of PutServiceMetaData")
+				return nil
+			},
+			putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
+				called = true
+				if serviceName != "foo" {
+					t.Errorf("unexpected service name: want %s, got %s", "foo", serviceName)
+				}
+				if roleName != "bar" {
+					t.Errorf("unexpected role name: want %s, got %s", "bar", roleName)
+				}
+				if namespace != "runbook" {
+					t.Errorf("unexpected namespace: want %s, got %s", "runbook", namespace)
+				}
+				if diff := cmp.Diff(v, any(doc)); diff != "" {
+					t.Errorf("metadata differs: (-got +want)\n%s", diff)
+				}
+				return nil
+			},
+			putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+				t.Errorf("unexpected call of PutHostMetaData")
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id123",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Metadata",
+		LogicalResourceID: "Metadata",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:role:foo:bar",
+			"Namespace": "runbook",
+			"Document":  doc,
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if !called {
+		t.Error("PutRoleMetaData is not called")
+	}
+	if id != "mkr:test-org:metadata:role:foo:bar:runbook" {
+		t.Errorf("unexpected metadata id: want %s, got %s", "mkr:test-org:metadata:role:foo:bar:runbook", id)
+	}
+}
+
+func TestCreateMetadata_reservedNamespace(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+				t.Errorf("the reserved namespace should not be overwritten")
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-id123",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Metadata",
+		LogicalResourceID: "Metadata",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:service:foo",
+			"Namespace": "cloudformation",
+			"Document":  map[string]any{},
+		},
+	}
+	_, _, err := f.Handle(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	var called bool
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			putHostMetaData: func(ctx context.Context, hostID, namespace string, v any) error {
+				called = true
+				if hostID != "3yAYEDLXKL5" {
+					t.Errorf("unexpected host id: want %s, got %s", "3yAYEDLXKL5", hostID)
+				}
+				if namespace != "owner" {
+					t.Errorf("unexpected namespace: want %s, got %s", "owner", namespace)
+				}
+				if diff := cmp.Diff(v, any("bob")); diff != "" {
+					t.Errorf("metadata differs: (-got +want)\n%s", diff)
+				}
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::Metadata",
+		LogicalResourceID:  "Metadata",
+		PhysicalResourceID: "mkr:test-org:metadata:host:3yAYEDLXKL5:owner",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:host:3yAYEDLXKL5",
+			"Namespace": "owner",
+			"Document":  "bob",
+		},
+		OldResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:host:3yAYEDLXKL5",
+			"Namespace": "owner",
+			"Document":  "alice",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if !called {
+		t.Error("PutHostMetaData is not called")
+	}
+	if id != "mkr:test-org:metadata:host:3yAYEDLXKL5:owner" {
+		t.Errorf("unexpected metadata id: want %s, got %s", "mkr:test-org:metadata:host:3yAYEDLXKL5:owner", id)
+	}
+}
+
+func TestUpdateMetadata_changeNamespace(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+				if namespace != "cost-center" {
+					t.Errorf("unexpected namespace: want %s, got %s", "cost-center", namespace)
+				}
+				return nil
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::Metadata",
+		LogicalResourceID:  "Metadata",
+		PhysicalResourceID: "mkr:test-org:metadata:service:foo:owner",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:service:foo",
+			"Namespace": "cost-center",
+			"Document":  "1234",
+		},
+		OldResourceProperties: map[string]any{
+			"Target":    "mkr:test-org:service:foo",
+			"Namespace": "owner",
+			"Document":  "1234",
+		},
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "mkr:test-org:metadata:service:foo:cost-center" {
+		t.Errorf("unexpected metadata id: want %s, got %s", "mkr:test-org:metadata:service:foo:cost-center", id)
+	}
+}
+
+func TestDeleteMetadata(t *testing.T) {
+	var called bool
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			deleteServiceMetaData: func(ctx context.Context, serviceName, namespace string) error {
+				called = true
+				if serviceName != "foo" {
+					t.Errorf("unexpected service name: want %s, got %s", "foo", serviceName)
+				}
+				if namespace != "owner" {
+					t.Errorf("unexpected namespace: want %s, got %s", "owner", namespace)
+				}
+				return mkrError{
+					statusCode: 404,
+					message:    "Metadata not found",
+				}
+			},
+		},
+	}
+
+	event := cfn.Event{
+		RequestType:        cfn.RequestDelete,
+		RequestID:          "request-id123",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::Metadata",
+		LogicalResourceID:  "Metadata",
+		PhysicalResourceID: "mkr:test-org:metadata:service:foo:owner",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:123456789012:stack/foobar/12345678-1234-1234-1234-123456789abc",
+	}
+	id, _, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Error(err)
+	}
+	if !called {
+		t.Error("DeleteServiceMetaData is not called")
+	}
+	if id != "mkr:test-org:metadata:service:foo:owner" {
+		t.Errorf("unexpected metadata id: want %s, got %s", "mkr:test-org:metadata:service:foo:owner", id)
+	}
+}
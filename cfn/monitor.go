@@ -23,7 +23,16 @@ func (m *monitor) create(ctx context.Context) (physicalResourceID string, data m
 	if err != nil {
 		return "", nil, err
 	}
-	ret, err := c.CreateMonitor(ctx, mm)
+	adopteeID, err := m.findAdoptee(ctx, mm)
+	if err != nil {
+		return "", nil, err
+	}
+	var ret mackerel.Monitor
+	if adopteeID != "" {
+		ret, err = c.UpdateMonitor(ctx, adopteeID, mm)
+	} else {
+		ret, err = c.CreateMonitor(ctx, mm)
+	}
 	if err != nil {
 		return "", nil, err
 	}
@@ -58,6 +67,13 @@ func (m *monitor) update(ctx context.Context) (physicalResourceID string, data m
 	if err != nil {
 		return m.Event.PhysicalResourceID, nil, err
 	}
+	current, err := c.FindMonitor(ctx, id)
+	if err != nil {
+		return m.Event.PhysicalResourceID, nil, err
+	}
+	if err := m.checkOwner(current); err != nil {
+		return m.Event.PhysicalResourceID, nil, err
+	}
 	ret, err := c.UpdateMonitor(ctx, id, mm)
 	if err != nil {
 		return m.Event.PhysicalResourceID, nil, err
@@ -70,6 +86,42 @@ func (m *monitor) update(ctx context.Context) (physicalResourceID string, data m
 	}, nil
 }
 
+// findAdoptee returns the id of the existing monitor that has the same name and type.
+// It returns an empty string if a new monitor should be created.
+func (m *monitor) findAdoptee(ctx context.Context, mm mackerel.Monitor) (string, error) {
+	if adopt, err := shouldAdopt(m.Event); err != nil || !adopt {
+		return "", err
+	}
+	c := m.Function.getclient()
+	monitors, err := c.FindMonitors(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, err := findByName("monitor", mm.MonitorName(), monitors, func(v mackerel.Monitor) (string, string) {
+		if v.MonitorType() != mm.MonitorType() {
+			return "", ""
+		}
+		return v.MonitorName(), v.MonitorID()
+	})
+	if err != nil || id == "" {
+		return "", err
+	}
+	for _, v := range monitors {
+		if v.MonitorID() == id {
+			if err := m.checkOwner(v); err != nil {
+				return "", err
+			}
+		}
+	}
+	return id, nil
+}
+
+// checkOwner returns an error if the monitor is owned by another stack.
+func (m *monitor) checkOwner(mon mackerel.Monitor) error {
+	resource := fmt.Sprintf("the monitor %s", mon.MonitorID())
+	return checkOwner(m.Event, resource, ownerFromMemo(mon.MonitorMemo()))
+}
+
 func (m *monitor) needsReplace() (bool, error) {
 	var d dproxy.Drain
 	in := dproxy.New(m.Event.ResourceProperties)
@@ -83,8 +135,24 @@ func (m *monitor) needsReplace() (bool, error) {
 	return typ != oldType, nil
 }
 
+// convertScopes converts the ids of services and roles into the scopes of monitors.
+func (m *monitor) convertScopes(ctx context.Context, d *dproxy.Drain, name string, items dproxy.Proxy) []string {
+	var scopes []string
+	for _, item := range d.Array(items) {
+		s := d.String(dproxy.New(item))
+		if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
+			scopes = append(scopes, serviceName)
+		} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
+			scopes = append(scopes, serviceName+":"+roleName)
+		} else {
+			d.Put(fmt.Errorf("%s should be a service of a role: %s", name, s))
+		}
+	}
+	return scopes
+}
+
 func (m *monitor) convertToParam(ctx context.Context, properties map[string]any) (mackerel.Monitor, error) {
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 	typ, err := in.M("Type").String()
 	if err != nil {
 		return nil, err
@@ -94,60 +162,22 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 	var mm mackerel.Monitor
 	switch typ {
 	case mackerel.MonitorTypeConnectivity.String():
-		var scopes, excludeScopes []string
-		for _, item := range d.Array(dproxy.Default(in.M("Scopes"), []any{})) {
-			s := d.String(dproxy.New(item))
-			if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName)
-			} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName+":"+roleName)
-			} else {
-				d.Put(fmt.Errorf("scopes should be a service of a role: %s", s))
-			}
-		}
-		for _, item := range d.Array(dproxy.Default(in.M("ExcludeScopes"), []any{})) {
-			s := d.String(dproxy.New(item))
-			if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
-				excludeScopes = append(excludeScopes, serviceName)
-			} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
-				excludeScopes = append(excludeScopes, serviceName+":"+roleName)
-			} else {
-				d.Put(fmt.Errorf("excludeScopes should be a service of a role: %s", s))
-			}
-		}
+		scopes := m.convertScopes(ctx, &d, "scopes", dproxy.Default(in.M("Scopes"), []any{}))
+		excludeScopes := m.convertScopes(ctx, &d, "excludeScopes", dproxy.Default(in.M("ExcludeScopes"), []any{}))
 		mm = &mackerel.MonitorConnectivity{
 			Name:                 d.String(in.M("Name")),
-			Memo:                 d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			Scopes:               scopes,
 			ExcludeScopes:        excludeScopes,
 			NotificationInterval: uint64(d.Int64(dproxy.Default(in.M("NotificationInterval"), 0))),
 			IsMute:               d.Bool(dproxy.Default(in.M("IsMute"), false)),
 		}
 	case mackerel.MonitorTypeHostMetric.String():
-		var scopes, excludeScopes []string
-		for _, item := range d.Array(dproxy.Default(in.M("Scopes"), []any{})) {
-			s := d.String(dproxy.New(item))
-			if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName)
-			} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName+":"+roleName)
-			} else {
-				d.Put(fmt.Errorf("scopes should be a service of a role: %s", s))
-			}
-		}
-		for _, item := range d.Array(dproxy.Default(in.M("ExcludeScopes"), []any{})) {
-			s := d.String(dproxy.New(item))
-			if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
-				excludeScopes = append(excludeScopes, serviceName)
-			} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
-				excludeScopes = append(excludeScopes, serviceName+":"+roleName)
-			} else {
-				d.Put(fmt.Errorf("excludeScopes should be a service of a role: %s", s))
-			}
-		}
+		scopes := m.convertScopes(ctx, &d, "scopes", dproxy.Default(in.M("Scopes"), []any{}))
+		excludeScopes := m.convertScopes(ctx, &d, "excludeScopes", dproxy.Default(in.M("ExcludeScopes"), []any{}))
 		mm = &mackerel.MonitorHostMetric{
 			Name:                 d.String(in.M("Name")),
-			Memo:                 d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			Duration:             d.Uint64(dproxy.Default(in.M("Duration"), 1)),
 			Metric:               d.String(in.M("Metric")),
 			Operator:             d.String(in.M("Operator")),
@@ -166,7 +196,7 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 		}
 		mm = &mackerel.MonitorServiceMetric{
 			Name:                    d.String(in.M("Name")),
-			Memo:                    d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:                    withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			Duration:                d.Uint64(dproxy.Default(in.M("Duration"), 1)),
 			Service:                 serviceName,
 			Metric:                  d.String(in.M("Metric")),
@@ -201,7 +231,7 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 		}
 		mm = &mackerel.MonitorExternalHTTP{
 			Name:        d.String(in.M("Name")),
-			Memo:        d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:        withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			URL:         d.String(in.M("Url")),
 			Method:      d.String(dproxy.Default(in.M("Method"), "GET")),
 			RequestBody: d.String(dproxy.Default(in.M("RequestBody"), "")),
@@ -223,7 +253,7 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 	case mackerel.MonitorTypeExpression.String():
 		mm = &mackerel.MonitorExpression{
 			Name:                 d.String(in.M("Name")),
-			Memo:                 d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			Expression:           d.String(in.M("Expression")),
 			Operator:             d.String(in.M("Operator")),
 			Warning:              d.OptionalFloat64(in.M("Warning")),
@@ -232,20 +262,10 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 			IsMute:               d.Bool(dproxy.Default(in.M("IsMute"), false)),
 		}
 	case mackerel.MonitorTypeAnomalyDetection.String():
-		var scopes []string
-		for _, item := range d.Array(in.M("Scopes")) {
-			s := d.String(dproxy.New(item))
-			if serviceName, err := m.Function.parseServiceID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName)
-			} else if serviceName, roleName, err := m.Function.parseRoleID(ctx, s); err == nil {
-				scopes = append(scopes, serviceName+":"+roleName)
-			} else {
-				d.Put(fmt.Errorf("scopes should be a service of a role: %s", s))
-			}
-		}
+		scopes := m.convertScopes(ctx, &d, "scopes", in.M("Scopes"))
 		mm = &mackerel.MonitorAnomalyDetection{
 			Name:                 d.String(in.M("Name")),
-			Memo:                 d.String(dproxy.Default(in.M("Memo"), "")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
 			Scopes:               scopes,
 			MaxCheckAttempts:     d.Uint64(dproxy.Default(in.M("MaxCheckAttempts"), 0)),
 			NotificationInterval: d.Uint64(dproxy.Default(in.M("NotificationInterval"), 0)),
@@ -254,9 +274,34 @@ func (m *monitor) convertToParam(ctx context.Context, properties map[string]any)
 			TrainingPeriodFrom:   mackerel.Timestamp(d.Int64(dproxy.Default(in.M("TrainingPeriodFrom"), 0))),
 			IsMute:               d.Bool(dproxy.Default(in.M("IsMute"), false)),
 		}
+	case mackerel.MonitorTypeCheck.String():
+		scopes := m.convertScopes(ctx, &d, "scopes", dproxy.Default(in.M("Scopes"), []any{}))
+		excludeScopes := m.convertScopes(ctx, &d, "excludeScopes", dproxy.Default(in.M("ExcludeScopes"), []any{}))
+		mm = &mackerel.MonitorCheck{
+			Name:                 d.String(in.M("Name")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
+			Scopes:               scopes,
+			ExcludeScopes:        excludeScopes,
+			NotificationInterval: d.Uint64(dproxy.Default(in.M("NotificationInterval"), 0)),
+			MaxCheckAttempts:     d.Uint64(dproxy.Default(in.M("MaxCheckAttempts"), 1)),
+			IsMute:               d.Bool(dproxy.Default(in.M("IsMute"), false)),
+		}
+	case mackerel.MonitorTypeQuery.String():
+		mm = &mackerel.MonitorQuery{
+			Name:                 d.String(in.M("Name")),
+			Memo:                 withOwner(d.String(dproxy.Default(in.M("Memo"), "")), m.Event),
+			Query:                d.String(in.M("Query")),
+			Legend:               d.String(dproxy.Default(in.M("Legend"), "")),
+			Operator:             d.String(in.M("Operator")),
+			Warning:              d.OptionalFloat64(in.M("Warning")),
+			Critical:             d.OptionalFloat64(in.M("Critical")),
+			NotificationInterval: d.Uint64(dproxy.Default(in.M("NotificationInterval"), 0)),
+			IsMute:               d.Bool(dproxy.Default(in.M("IsMute"), false)),
+		}
 	default:
 		return nil, fmt.Errorf("unknown monitor type: %s", typ)
 	}
+	checkUnknownProperties(&d, m.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
@@ -273,8 +318,21 @@ func (m *monitor) delete(ctx context.Context) (physicalResourceID string, data m
 	}
 
 	c := m.Function.getclient()
-	_, err = c.DeleteMonitor(ctx, id)
 	var merr mackerel.Error
+	current, err := c.FindMonitor(ctx, id)
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		log.Printf("It seems that the monitor %q is already deleted, ignore the error: %s", physicalResourceID, err)
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	if err = m.checkOwner(current); err != nil {
+		return
+	}
+
+	_, err = c.DeleteMonitor(ctx, id)
 	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
 		log.Printf("It seems that the role %q is already deleted, ignore the error: %s", physicalResourceID, err)
 		err = nil
@@ -18,7 +18,7 @@ func TestCreateMonitor_MonitorConnectivity(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorConnectivity{
 					Name:                 "foo-bar",
-					Memo:                 "monitor",
+					Memo:                 "monitor\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					NotificationInterval: 60,
 					Scopes:               []string{"my-service"},
 					ExcludeScopes:        []string{"my-service:my-role"},
@@ -76,7 +76,7 @@ func TestCreateMonitor_MonitorHostMetric(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorHostMetric{
 					Name:                 "disk.aa-00.writes.delta",
-					Memo:                 "This monitor is for Hatena Blog.",
+					Memo:                 "This monitor is for Hatena Blog.\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					Duration:             3,
 					Metric:               "disk.aa-00.writes.delta",
 					Operator:             ">",
@@ -146,7 +146,7 @@ func TestCreateMonitor_MonitorServiceMetric(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorServiceMetric{
 					Name:                    "Hatena-Blog - access_num.4xx_count",
-					Memo:                    "A monitor that checks the number of 4xx for Hatena Blog",
+					Memo:                    "A monitor that checks the number of 4xx for Hatena Blog\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					Duration:                1,
 					Service:                 "Hatena-Blog",
 					Metric:                  "access_num.4xx_count",
@@ -218,7 +218,7 @@ func TestCreateMonitor_MonitorExternalHTTP(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorExternalHTTP{
 					Name:                 "Example Domain",
-					Memo:                 "Monitors example.com",
+					Memo:                 "Monitors example.com\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					NotificationInterval: 60,
 
 					Method:                          "GET",
@@ -305,7 +305,7 @@ func TestCreateMonitor_MonitorExpression(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorExpression{
 					Name:                 "role average",
-					Memo:                 "Monitors the average of loadavg5",
+					Memo:                 "Monitors the average of loadavg5\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					NotificationInterval: 60,
 
 					Expression: `avg(roleSlots("server:role","loadavg5"))`,
@@ -368,7 +368,7 @@ func TestCreateMonitor_MonitorAnomalyDetection(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorAnomalyDetection{
 					Name:               "anomaly detection",
-					Memo:               "my anomaly detection for roles",
+					Memo:               "my anomaly detection for roles\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 					Scopes:             []string{"myService", "myService:myRole"},
 					WarningSensitivity: mackerel.AnomalyDetectionSensitivityInsensitive,
 					IsMute:             true,
@@ -418,15 +418,138 @@ func TestCreateMonitor_MonitorAnomalyDetection(t *testing.T) {
 	}
 }
 
+func TestCreateMonitor_MonitorCheck(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
+				want := &mackerel.MonitorCheck{
+					Name:                 "check-cron",
+					Memo:                 "check the cron daemon\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					NotificationInterval: 60,
+					MaxCheckAttempts:     3,
+					Scopes:               []string{"my-service"},
+					ExcludeScopes:        []string{"my-service:my-role"},
+					IsMute:               true,
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("monitor differs: (-got +want)\n%s", diff)
+				}
+				want.ID = "3yAYEDLXKL5"
+				return want, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Monitor",
+		LogicalResourceID: "Monitor",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":                 "check",
+			"Name":                 "check-cron",
+			"Memo":                 "check the cron daemon",
+			"Scopes":               []any{"mkr:test-org:service:my-service"},
+			"ExcludeScopes":        []any{"mkr:test-org:role:my-service:my-role"},
+			"NotificationInterval": 60,
+			"MaxCheckAttempts":     3,
+			"IsMute":               true,
+		},
+	}
+	id, param, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:monitor:3yAYEDLXKL5" {
+		t.Errorf("unexpected monitor id: want %s, got %s", "mkr:test-org:monitor:3yAYEDLXKL5", id)
+	}
+	if param["MonitorId"].(string) != "3yAYEDLXKL5" {
+		t.Errorf("unexpected monitor id, want %s, got %s", "3yAYEDLXKL5", param["MonitorId"].(string))
+	}
+	if param["Name"].(string) != "check-cron" {
+		t.Errorf("unexpected name, want %s, got %s", "check-cron", param["Name"].(string))
+	}
+	if param["Type"].(string) != "check" {
+		t.Errorf("unexpected type, want %s, got %s", "check", param["Type"].(string))
+	}
+}
+
+func TestCreateMonitor_MonitorQuery(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
+				want := &mackerel.MonitorQuery{
+					Name:                 "http latency",
+					Memo:                 "p99 latency of my service\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+					Query:                "histogram_quantile(0.99, http.server.duration)",
+					Legend:               "{{service.name}}",
+					Operator:             ">",
+					Warning:              new(0.5),
+					Critical:             new(1.0),
+					NotificationInterval: 60,
+				}
+				if diff := cmp.Diff(param, want); diff != "" {
+					t.Errorf("monitor differs: (-got +want)\n%s", diff)
+				}
+				want.ID = "3yAYEDLXKL5"
+				return want, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "",
+		ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:      "Custom::Monitor",
+		LogicalResourceID: "Monitor",
+		StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":                 "query",
+			"Name":                 "http latency",
+			"Memo":                 "p99 latency of my service",
+			"Query":                "histogram_quantile(0.99, http.server.duration)",
+			"Legend":               "{{service.name}}",
+			"Operator":             ">",
+			"Warning":              "0.5",
+			"Critical":             "1.0",
+			"NotificationInterval": "60",
+		},
+	}
+	id, param, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:monitor:3yAYEDLXKL5" {
+		t.Errorf("unexpected monitor id: want %s, got %s", "mkr:test-org:monitor:3yAYEDLXKL5", id)
+	}
+	if param["Type"].(string) != "query" {
+		t.Errorf("unexpected type, want %s, got %s", "query", param["Type"].(string))
+	}
+}
+
 func TestUpdateMonitor_updateMutable(t *testing.T) {
 	f := &Function{
 		org: &mackerel.Org{
 			Name: "test-org",
 		},
 		client: &fakeMackerelClient{
+			findMonitor: func(ctx context.Context, monitorID string) (mackerel.Monitor, error) {
+				return &mackerel.MonitorConnectivity{
+					ID:   monitorID,
+					Name: "bar",
+				}, nil
+			},
 			updateMonitor: func(ctx context.Context, monitorID string, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorConnectivity{
 					Name: "foo",
+					Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 				}
 				if diff := cmp.Diff(param, want); diff != "" {
 					t.Errorf("monitor differs: (-got +want)\n%s", diff)
@@ -483,6 +606,7 @@ func TestUpdateMonitor_updateImmutable(t *testing.T) {
 			createMonitor: func(ctx context.Context, param mackerel.Monitor) (mackerel.Monitor, error) {
 				want := &mackerel.MonitorConnectivity{
 					Name: "foo-bar",
+					Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 				}
 				if diff := cmp.Diff(param, want); diff != "" {
 					t.Errorf("monitor differs: (-got +want)\n%s", diff)
@@ -533,6 +657,13 @@ func TestDeleteMonitor(t *testing.T) {
 			Name: "test-org",
 		},
 		client: &fakeMackerelClient{
+			findMonitor: func(ctx context.Context, monitorID string) (mackerel.Monitor, error) {
+				return &mackerel.MonitorConnectivity{
+					ID:   monitorID,
+					Name: "foo-bar",
+					Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+				}, nil
+			},
 			deleteMonitor: func(ctx context.Context, id string) (mackerel.Monitor, error) {
 				if id != "delete-monitor" {
 					t.Errorf("unexpected monitor id: want %s, got %s", "delete-monitor", id)
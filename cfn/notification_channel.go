@@ -23,6 +23,21 @@ func (ch *notificationChannel) create(ctx context.Context) (physicalResourceID s
 	if err != nil {
 		return "", nil, err
 	}
+	adopteeID, err := ch.findAdoptee(ctx, param)
+	if err != nil {
+		return "", nil, err
+	}
+	if adopteeID != "" {
+		// Mackerel has no api for updating notification channels, so the adopted one is kept as it is.
+		// The next update of the properties replaces it with a new one.
+		id, err := ch.Function.buildNotificationChannelID(ctx, adopteeID)
+		if err != nil {
+			return "", nil, err
+		}
+		return id, map[string]any{
+			"Name": param.NotificationChannelName(),
+		}, nil
+	}
 	ret, err := c.CreateNotificationChannel(ctx, param)
 	if err != nil {
 		return "", nil, err
@@ -38,20 +53,40 @@ func (ch *notificationChannel) create(ctx context.Context) (physicalResourceID s
 }
 
 func (ch *notificationChannel) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
+	// Mackerel has no api for updating notification channels.
 	return ch.create(ctx) // create new one and replace
 }
 
+// findAdoptee returns the id of the existing notification channel that has the same name and type.
+// It returns an empty string if a new notification channel should be created.
+func (ch *notificationChannel) findAdoptee(ctx context.Context, param mackerel.NotificationChannel) (string, error) {
+	if adopt, err := shouldAdopt(ch.Event); err != nil || !adopt {
+		return "", err
+	}
+	c := ch.Function.getclient()
+	channels, err := c.FindNotificationChannels(ctx)
+	if err != nil {
+		return "", err
+	}
+	return findByName("notification channel", param.NotificationChannelName(), channels, func(v mackerel.NotificationChannel) (string, string) {
+		if v.NotificationChannelType() != param.NotificationChannelType() {
+			return "", ""
+		}
+		return v.NotificationChannelName(), v.NotificationChannelID()
+	})
+}
+
 func (ch *notificationChannel) convertToParam(ctx context.Context, properties map[string]any) (mackerel.NotificationChannel, error) {
 	var ret mackerel.NotificationChannel
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 	typ := d.String(in.M("Type"))
 	switch typ {
 	case mackerel.NotificationChannelTypeEmail.String():
 		ret = &mackerel.NotificationChannelEmail{
 			Name:    d.String(in.M("Name")),
-			Emails:  d.StringArray(in.M("Emails").ProxySet()),
-			UserIDs: []string{}, // TODO: support user ids
+			Emails:  d.StringArray(dproxy.Default(in.M("Emails"), []any{}).ProxySet()),
+			UserIDs: ch.convertUsers(ctx, &d, dproxy.Default(in.M("Users"), []any{})),
 			Events:  ch.convertEvents(&d, in.M("Events")),
 		}
 	case mackerel.NotificationChannelTypeSlack.String():
@@ -72,9 +107,63 @@ func (ch *notificationChannel) convertToParam(ctx context.Context, properties ma
 			URL:    d.String(in.M("Url")),
 			Events: ch.convertEvents(&d, in.M("Events")),
 		}
+	case mackerel.NotificationChannelTypeLine.String():
+		ret = &mackerel.NotificationChannelLine{
+			Name:   d.String(in.M("Name")),
+			Token:  d.String(in.M("Token")),
+			Events: ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeChatwork.String():
+		ret = &mackerel.NotificationChannelChatwork{
+			Name:   d.String(in.M("Name")),
+			Token:  d.String(in.M("Token")),
+			RoomID: d.String(in.M("RoomId")),
+			Events: ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeTypetalk.String():
+		ret = &mackerel.NotificationChannelTypetalk{
+			Name:   d.String(in.M("Name")),
+			URL:    d.String(in.M("Url")),
+			Events: ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeTwilio.String():
+		ret = &mackerel.NotificationChannelTwilio{
+			Name:       d.String(in.M("Name")),
+			AccountSID: d.String(in.M("AccountSid")),
+			AuthToken:  d.String(in.M("AuthToken")),
+			From:       d.String(in.M("From")),
+			To:         d.String(in.M("To")),
+			Events:     ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypePagerduty.String():
+		ret = &mackerel.NotificationChannelPagerduty{
+			Name:       d.String(in.M("Name")),
+			ServiceKey: d.String(in.M("ServiceKey")),
+			Events:     ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeOpsgenie.String():
+		ret = &mackerel.NotificationChannelOpsgenie{
+			Name:   d.String(in.M("Name")),
+			APIKey: d.String(in.M("ApiKey")),
+			Events: ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeMicrosoftTeams.String():
+		ret = &mackerel.NotificationChannelMicrosoftTeams{
+			Name:   d.String(in.M("Name")),
+			URL:    d.String(in.M("Url")),
+			Events: ch.convertEvents(&d, in.M("Events")),
+		}
+	case mackerel.NotificationChannelTypeAmazonEventBridge.String():
+		ret = &mackerel.NotificationChannelAmazonEventBridge{
+			Name:         d.String(in.M("Name")),
+			AWSAccountID: d.String(in.M("AwsAccountId")),
+			Region:       d.String(in.M("Region")),
+			Events:       ch.convertEvents(&d, in.M("Events")),
+		}
 	default:
 		return nil, fmt.Errorf("unknown type: %s", typ)
 	}
+	checkUnknownProperties(&d, ch.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
@@ -90,6 +179,60 @@ func (ch *notificationChannel) convertEvents(d *dproxy.Drain, in dproxy.Proxy) [
 	return ret
 }
 
+// convertUsers converts the physical ids of Mackerel::User into the user ids of Mackerel.
+func (ch *notificationChannel) convertUsers(ctx context.Context, d *dproxy.Drain, in dproxy.Proxy) []string {
+	ids := d.StringArray(in.ProxySet())
+	ret := make([]string, 0, len(ids))
+	if len(ids) == 0 {
+		return ret
+	}
+
+	c := ch.Function.getclient()
+	users, err := c.FindUsers(ctx)
+	if err != nil {
+		d.Put(err)
+		return ret
+	}
+
+	userIDs := make(map[string]string, len(users))
+	for _, u := range users {
+		userIDs[u.Email] = u.ID
+	}
+
+	var invited map[string]bool
+	for _, id := range ids {
+		email, err := ch.Function.parseUserID(ctx, id)
+		if err != nil {
+			d.Put(fmt.Errorf("failed to parse %q as user id: %w", id, err))
+			continue
+		}
+		if uid, ok := userIDs[email]; ok {
+			ret = append(ret, uid)
+			continue
+		}
+
+		// the user is not found in the org.
+		// check whether the user is still invited for better error message.
+		if invited == nil {
+			invitations, err := c.FindInvitations(ctx)
+			if err != nil {
+				d.Put(err)
+				return ret
+			}
+			invited = make(map[string]bool, len(invitations))
+			for _, invite := range invitations {
+				invited[invite.Email] = true
+			}
+		}
+		if invited[email] {
+			d.Put(fmt.Errorf("user %s has not accepted the invitation yet", email))
+		} else {
+			d.Put(fmt.Errorf("user %s is not found in the organization", email))
+		}
+	}
+	return ret
+}
+
 func (ch *notificationChannel) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	physicalResourceID = ch.Event.PhysicalResourceID
 	id, err := ch.Function.parseNotificationChannelID(ctx, physicalResourceID)
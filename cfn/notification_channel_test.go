@@ -2,9 +2,11 @@ package cfn
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/google/go-cmp/cmp"
 	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
 )
 
@@ -58,3 +60,293 @@ func TestCreateNotificationChannel(t *testing.T) {
 		t.Errorf("unexpected name, want %s, got %s", "channel-foobar", param["Name"].(string))
 	}
 }
+
+func TestUpdateNotificationChannel(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			createNotificationChannel: func(ctx context.Context, ch mackerel.NotificationChannel) (mackerel.NotificationChannel, error) {
+				want := &mackerel.NotificationChannelWebHook{
+					Name:   "channel-foobar",
+					URL:    "https://example.com/webhook",
+					Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+				}
+				if diff := cmp.Diff(ch, want); diff != "" {
+					t.Errorf("notification channel differs: (-got +want)\n%s", diff)
+				}
+				want.ID = "new-id"
+				return want, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::NotificationChannel",
+		LogicalResourceID:  "Channel",
+		PhysicalResourceID: "mkr:test-org:notification-channel:old-id",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type":   "webhook",
+			"Name":   "channel-foobar",
+			"Url":    "https://example.com/webhook",
+			"Events": []any{"alert"},
+		},
+		OldResourceProperties: map[string]any{
+			"Type":   "webhook",
+			"Name":   "channel-old",
+			"Url":    "https://example.com/webhook",
+			"Events": []any{"alert"},
+		},
+	}
+	id, param, err := f.Handle(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:notification-channel:new-id" {
+		t.Errorf("unexpected notification channel id: want %s, got %s", "mkr:test-org:notification-channel:new-id", id)
+	}
+	if param["Name"].(string) != "channel-foobar" {
+		t.Errorf("unexpected name, want %s, got %s", "channel-foobar", param["Name"].(string))
+	}
+}
+
+func TestNotificationChannelConvertToParam(t *testing.T) {
+	tests := []struct {
+		in   map[string]any
+		want mackerel.NotificationChannel
+	}{
+		{
+			in: map[string]any{
+				"Type":   "line",
+				"Name":   "channel-foobar",
+				"Token":  "line-notify-token",
+				"Events": []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelLine{
+				Name:   "channel-foobar",
+				Token:  "line-notify-token",
+				Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":   "chatwork",
+				"Name":   "channel-foobar",
+				"Token":  "chatwork-api-token",
+				"RoomId": "123456",
+				"Events": []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelChatwork{
+				Name:   "channel-foobar",
+				Token:  "chatwork-api-token",
+				RoomID: "123456",
+				Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":   "typetalk",
+				"Name":   "channel-foobar",
+				"Url":    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+				"Events": []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelTypetalk{
+				Name:   "channel-foobar",
+				URL:    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+				Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":       "twilio",
+				"Name":       "channel-foobar",
+				"AccountSid": "AC0123456789",
+				"AuthToken":  "twilio-auth-token",
+				"From":       "+815012345678",
+				"To":         "+819012345678",
+				"Events":     []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelTwilio{
+				Name:       "channel-foobar",
+				AccountSID: "AC0123456789",
+				AuthToken:  "twilio-auth-token",
+				From:       "+815012345678",
+				To:         "+819012345678",
+				Events:     []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":       "pagerduty",
+				"Name":       "channel-foobar",
+				"ServiceKey": "pagerduty-integration-key",
+				"Events":     []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelPagerduty{
+				Name:       "channel-foobar",
+				ServiceKey: "pagerduty-integration-key",
+				Events:     []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":   "opsgenie",
+				"Name":   "channel-foobar",
+				"ApiKey": "opsgenie-api-key",
+				"Events": []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelOpsgenie{
+				Name:   "channel-foobar",
+				APIKey: "opsgenie-api-key",
+				Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":   "microsoft-teams",
+				"Name":   "channel-foobar",
+				"Url":    "https://example.webhook.office.com/webhookb2/XXXXX",
+				"Events": []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelMicrosoftTeams{
+				Name:   "channel-foobar",
+				URL:    "https://example.webhook.office.com/webhookb2/XXXXX",
+				Events: []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+		{
+			in: map[string]any{
+				"Type":         "amazon-event-bridge",
+				"Name":         "channel-foobar",
+				"AwsAccountId": "123456789012",
+				"Region":       "ap-northeast-1",
+				"Events":       []any{"alert"},
+			},
+			want: &mackerel.NotificationChannelAmazonEventBridge{
+				Name:         "channel-foobar",
+				AWSAccountID: "123456789012",
+				Region:       "ap-northeast-1",
+				Events:       []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+			},
+		},
+	}
+
+	ch := &notificationChannel{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in["Type"].(string), func(t *testing.T) {
+			got, err := ch.convertToParam(context.Background(), tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("notification channel differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotificationChannelConvertToParam_unknownType(t *testing.T) {
+	ch := &notificationChannel{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+		},
+	}
+	_, err := ch.convertToParam(context.Background(), map[string]any{
+		"Type":   "hipchat",
+		"Name":   "channel-foobar",
+		"Events": []any{"alert"},
+	})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestNotificationChannelConvertToParam_emailUsers(t *testing.T) {
+	ch := &notificationChannel{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				findUsers: func(ctx context.Context) ([]*mackerel.User, error) {
+					return []*mackerel.User{
+						{
+							ID:    "user-john-doe",
+							Email: "john.doe@example.com",
+						},
+						{
+							ID:    "user-jane-doe",
+							Email: "jane.doe@example.com",
+						},
+					}, nil
+				},
+			},
+		},
+	}
+	got, err := ch.convertToParam(context.Background(), map[string]any{
+		"Type":   "email",
+		"Name":   "channel-foobar",
+		"Emails": []any{"alice@example.com"},
+		"Users":  []any{"mkr:test-org:user:jane.doe@example.com"},
+		"Events": []any{"alert"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &mackerel.NotificationChannelEmail{
+		Name:    "channel-foobar",
+		Emails:  []string{"alice@example.com"},
+		UserIDs: []string{"user-jane-doe"},
+		Events:  []mackerel.NotificationEvent{mackerel.NotificationEventAlert},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("notification channel differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestNotificationChannelConvertToParam_emailUsersNotAccepted(t *testing.T) {
+	ch := &notificationChannel{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				findUsers: func(ctx context.Context) ([]*mackerel.User, error) {
+					return []*mackerel.User{}, nil
+				},
+				findInvitations: func(ctx context.Context) ([]*mackerel.Invitation, error) {
+					return []*mackerel.Invitation{
+						{
+							Email:     "john.doe@example.com",
+							Authority: mackerel.UserAuthorityViewer,
+						},
+					}, nil
+				},
+			},
+		},
+	}
+	_, err := ch.convertToParam(context.Background(), map[string]any{
+		"Type":   "email",
+		"Name":   "channel-foobar",
+		"Users":  []any{"mkr:test-org:user:john.doe@example.com"},
+		"Events": []any{"alert"},
+	})
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if !strings.Contains(err.Error(), "john.doe@example.com has not accepted the invitation yet") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
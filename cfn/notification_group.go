@@ -22,7 +22,16 @@ func (g *notificationGroup) create(ctx context.Context) (physicalResourceID stri
 	if err != nil {
 		return
 	}
-	ret, err := c.CreateNotificationGroup(ctx, param)
+	adopteeID, err := g.findAdoptee(ctx, param)
+	if err != nil {
+		return
+	}
+	var ret *mackerel.NotificationGroup
+	if adopteeID != "" {
+		ret, err = c.UpdateNotificationGroup(ctx, adopteeID, param)
+	} else {
+		ret, err = c.CreateNotificationGroup(ctx, param)
+	}
 	if err != nil {
 		return
 	}
@@ -33,9 +42,25 @@ func (g *notificationGroup) create(ctx context.Context) (physicalResourceID stri
 	return
 }
 
+// findAdoptee returns the id of the existing notification group that has the same name.
+// It returns an empty string if a new notification group should be created.
+func (g *notificationGroup) findAdoptee(ctx context.Context, param *mackerel.NotificationGroup) (string, error) {
+	if adopt, err := shouldAdopt(g.Event); err != nil || !adopt {
+		return "", err
+	}
+	c := g.Function.getclient()
+	groups, err := c.FindNotificationGroups(ctx)
+	if err != nil {
+		return "", err
+	}
+	return findByName("notification group", param.Name, groups, func(v *mackerel.NotificationGroup) (string, string) {
+		return v.Name, v.ID
+	})
+}
+
 func (g *notificationGroup) convertToParam(ctx context.Context, properties map[string]any) (*mackerel.NotificationGroup, error) {
 	var d dproxy.Drain
-	in := dproxy.New(properties)
+	in, tracker := dproxy.Track(properties)
 	param := &mackerel.NotificationGroup{
 		Name: d.String(in.M("Name")),
 		NotificationLevel: mackerel.NotificationLevel(
@@ -83,6 +108,7 @@ func (g *notificationGroup) convertToParam(ctx context.Context, properties map[s
 			SkipDefault: d.Bool(dproxy.Default(m.M("SkipDefault"), false)),
 		})
 	}
+	checkUnknownProperties(&d, g.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,126 @@
+package cfn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// ownerMemoPrefix is the prefix of the line that records the owner stack in memos.
+// It is used for the resources that don't support metadata.
+const ownerMemoPrefix = "Managed by CloudFormation stack: "
+
+// withOwner appends the owner stack of the event to the memo.
+// The existing owner line is replaced.
+func withOwner(memo string, event cfn.Event) string {
+	memo = strings.TrimRight(stripOwner(memo), "\n")
+	owner := ownerMemoPrefix + event.StackID
+	if memo == "" {
+		return owner
+	}
+	return memo + "\n\n" + owner
+}
+
+// stripOwner removes the owner stack from the memo.
+func stripOwner(memo string) string {
+	lines := strings.Split(memo, "\n")
+	ret := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, ownerMemoPrefix) {
+			ret = append(ret, line)
+		}
+	}
+	return strings.Join(ret, "\n")
+}
+
+// ownerFromMemo returns the owner stack recorded in the memo.
+// It returns an empty string if no owner is recorded.
+func ownerFromMemo(memo string) string {
+	var owner string
+	for line := range strings.SplitSeq(memo, "\n") {
+		if id, ok := strings.CutPrefix(line, ownerMemoPrefix); ok {
+			owner = strings.TrimSpace(id)
+		}
+	}
+	return owner
+}
+
+// ownerFromMetadata returns the owner stack recorded in the "cloudformation" namespace of the metadata.
+// get should read the metadata into v.
+// It returns an empty string if no owner is recorded.
+func ownerFromMetadata(get func(v any) error) (string, error) {
+	var meta metadata
+	err := get(&meta)
+	var merr mackerel.Error
+	if errors.As(err, &merr) && merr.StatusCode() == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return meta.StackID, nil
+}
+
+// checkOwner returns an error if the resource is owned by another stack.
+// The resources that have no owner are considered to be owned by the stack,
+// because they may have been created before the owner was recorded.
+// The check is skipped if the ForceOwnership property is true.
+func checkOwner(event cfn.Event, resource, owner string) error {
+	if owner == "" || owner == event.StackID {
+		return nil
+	}
+
+	in := dproxy.New(event.ResourceProperties)
+	force, err := dproxy.Default(in.M("ForceOwnership"), false).Bool()
+	if err != nil {
+		return err
+	}
+	if force {
+		log.Printf("%s is owned by another stack %s, but ForceOwnership is set", resource, owner)
+		return nil
+	}
+	return fmt.Errorf("%s is owned by another stack %s; set ForceOwnership to take it over", resource, owner)
+}
+
+func (f *Function) checkServiceOwner(ctx context.Context, event cfn.Event, serviceName string) error {
+	c := f.getclient()
+	owner, err := ownerFromMetadata(func(v any) error {
+		_, err := c.GetServiceMetaData(ctx, serviceName, "cloudformation", v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return checkOwner(event, fmt.Sprintf("the service %s", serviceName), owner)
+}
+
+func (f *Function) checkRoleOwner(ctx context.Context, event cfn.Event, serviceName, roleName string) error {
+	c := f.getclient()
+	owner, err := ownerFromMetadata(func(v any) error {
+		_, err := c.GetRoleMetaData(ctx, serviceName, roleName, "cloudformation", v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return checkOwner(event, fmt.Sprintf("the role %s:%s", serviceName, roleName), owner)
+}
+
+func (f *Function) checkHostOwner(ctx context.Context, event cfn.Event, hostID string) error {
+	c := f.getclient()
+	owner, err := ownerFromMetadata(func(v any) error {
+		_, err := c.GetHostMetaData(ctx, hostID, "cloudformation", v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return checkOwner(event, fmt.Sprintf("the host %s", hostID), owner)
+}
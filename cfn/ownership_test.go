@@ -0,0 +1,260 @@
+package cfn
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestWithOwner(t *testing.T) {
+	event := cfn.Event{
+		StackID: "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+	}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "",
+			want: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+		{
+			in:   "memo",
+			want: "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+		{
+			// the owner is replaced.
+			in:   "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc",
+			want: "memo\n\nManaged by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+	}
+	for _, tc := range tests {
+		got := withOwner(tc.in, event)
+		if got != tc.want {
+			t.Errorf("withOwner(%q): want %q, got %q", tc.in, tc.want, got)
+		}
+		if owner := ownerFromMemo(got); owner != event.StackID {
+			t.Errorf("ownerFromMemo(%q): want %q, got %q", got, event.StackID, owner)
+		}
+	}
+}
+
+func TestOwnerFromMemo(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"memo", ""},
+		{"Managed by CloudFormation stack: stack-id", "stack-id"},
+		{"memo\n\nManaged by CloudFormation stack: stack-id\n", "stack-id"},
+	}
+	for _, tc := range tests {
+		if got := ownerFromMemo(tc.in); got != tc.want {
+			t.Errorf("ownerFromMemo(%q): want %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestDeleteService_ownedByAnotherStack(t *testing.T) {
+	s := &service{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.ServiceMetaMetaData{}, nil
+				},
+				deleteService: func(ctx context.Context, serviceName string) (*mackerel.Service, error) {
+					t.Error("the service owned by another stack should not be deleted")
+					return nil, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestDelete,
+			RequestID:          "",
+			ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:       "Custom:Service",
+			LogicalResourceID:  "Service",
+			PhysicalResourceID: "mkr:test-org:service:awesome-service",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name": "awesome-service",
+			},
+		},
+	}
+	_, _, err := s.delete(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ForceOwnership") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteService_forceOwnership(t *testing.T) {
+	var deleted bool
+	s := &service{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.ServiceMetaMetaData{}, nil
+				},
+				deleteService: func(ctx context.Context, serviceName string) (*mackerel.Service, error) {
+					deleted = true
+					return &mackerel.Service{
+						Name: serviceName,
+					}, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestDelete,
+			RequestID:          "",
+			ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:       "Custom:Service",
+			LogicalResourceID:  "Service",
+			PhysicalResourceID: "mkr:test-org:service:awesome-service",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Name":           "awesome-service",
+				"ForceOwnership": "true",
+			},
+		},
+	}
+	if _, _, err := s.delete(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if !deleted {
+		t.Error("the service is not deleted")
+	}
+}
+
+func TestCreateRole_ownedByAnotherStack(t *testing.T) {
+	r := &role{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createRole: func(ctx context.Context, serviceName string, param *mackerel.CreateRoleParam) (*mackerel.Role, error) {
+					return nil, mkrError{
+						statusCode: http.StatusBadRequest,
+					}
+				},
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.RoleMetaMetaData{}, nil
+				},
+				putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
+					t.Error("the role owned by another stack should not be overwritten")
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:       cfn.RequestCreate,
+			RequestID:         "",
+			ResponseURL:       "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:      "Custom:Role",
+			LogicalResourceID: "Role",
+			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			ResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:awesome-service",
+				"Name":    "role-hogehoge",
+			},
+		},
+	}
+	_, _, err := r.create(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ForceOwnership") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateMonitor_ownedByAnotherStack(t *testing.T) {
+	f := &Function{
+		org: &mackerel.Org{
+			Name: "test-org",
+		},
+		client: &fakeMackerelClient{
+			findMonitor: func(ctx context.Context, monitorID string) (mackerel.Monitor, error) {
+				return &mackerel.MonitorConnectivity{
+					ID:   monitorID,
+					Name: "bar",
+					Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc",
+				}, nil
+			},
+			updateMonitor: func(ctx context.Context, monitorID string, param mackerel.Monitor) (mackerel.Monitor, error) {
+				t.Error("the monitor owned by another stack should not be updated")
+				return param, nil
+			},
+		},
+	}
+	event := cfn.Event{
+		RequestType:        cfn.RequestUpdate,
+		RequestID:          "",
+		ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+		ResourceType:       "Custom::Monitor",
+		LogicalResourceID:  "Monitor",
+		PhysicalResourceID: "mkr:test-org:monitor:3yAYEDLXKL5",
+		StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		ResourceProperties: map[string]any{
+			"Type": "connectivity",
+			"Name": "foo",
+		},
+		OldResourceProperties: map[string]any{
+			"Type": "connectivity",
+			"Name": "bar",
+		},
+	}
+	_, _, err := f.Handle(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "ForceOwnership") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteDowntime_ownedByAnotherStack(t *testing.T) {
+	r := &downtime{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				findDowntimes: func(ctx context.Context) ([]*mackerel.Downtime, error) {
+					return []*mackerel.Downtime{
+						{
+							ID:   "3yAYEDLXKL5",
+							Name: "Maintenance #1",
+							Memo: "Managed by CloudFormation stack: arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc",
+						},
+					}, nil
+				},
+				deleteDowntime: func(ctx context.Context, downtimeID string) (*mackerel.Downtime, error) {
+					t.Error("the downtime owned by another stack should not be deleted")
+					return nil, nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestDelete,
+			RequestID:          "",
+			ResponseURL:        "https://cloudformation-custom-resource-response-apnortheast1.s3-ap-northeast-1.amazonaws.com/xxxxx",
+			ResourceType:       "Custom:Downtime",
+			LogicalResourceID:  "Downtime",
+			PhysicalResourceID: "mkr:test-org:downtime:3yAYEDLXKL5",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+	}
+	_, _, err := r.delete(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ForceOwnership") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package cfn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+)
+
+// commonProperties are the top-level properties that are not read by the converters.
+// ServiceToken is required by CloudFormation, and ApiKeyParameter and ApiKeySecret are handled by forResource.
+var commonProperties = map[string]bool{
+	"ServiceToken":    true,
+	"ApiKeyParameter": true,
+	"ApiKeySecret":    true,
+}
+
+// adoptableTypes are the resource types that support the Adopt property, which is handled by shouldAdopt.
+var adoptableTypes = map[string]bool{
+	"Monitor":             true,
+	"Dashboard":           true,
+	"NotificationChannel": true,
+	"NotificationGroup":   true,
+	"Downtime":            true,
+}
+
+// ownedTypes are the resource types that support the ForceOwnership property, which is handled by checkOwner.
+var ownedTypes = map[string]bool{
+	"Service":           true,
+	"Role":              true,
+	"Host":              true,
+	"Monitor":           true,
+	"Dashboard":         true,
+	"Downtime":          true,
+	"AlertGroupSetting": true,
+}
+
+// isCommonProperty reports whether the top-level property is handled outside of the converter of the type.
+func isCommonProperty(typ, key string) bool {
+	switch key {
+	case "Adopt":
+		return adoptableTypes[typ]
+	case "ForceOwnership":
+		return ownedTypes[typ]
+	}
+	return commonProperties[key]
+}
+
+// checkUnknownProperties reports the properties that the converter didn't read, which are usually typos.
+// It should be called after all the properties are read through the proxy tracked by tracker.
+//
+// The properties are checked only for the new properties of creations and updates.
+// Delete requests are never rejected, and the unknown properties which already exist in the old properties are allowed,
+// so that the stacks created before the check are still able to be updated, rolled back and deleted.
+func checkUnknownProperties(d *dproxy.Drain, event cfn.Event, tracker *dproxy.Tracker) {
+	if event.RequestType == cfn.RequestDelete {
+		return
+	}
+	var old map[string]bool
+	if event.RequestType == cfn.RequestUpdate {
+		old = make(map[string]bool)
+		addresses("", event.OldResourceProperties, old)
+	}
+
+	typ := strings.TrimPrefix(event.ResourceType, "Custom::")
+	for _, key := range tracker.Unvisited() {
+		if key.Address == key.Key && isCommonProperty(typ, key.Key) {
+			continue
+		}
+		if old[key.Address] {
+			continue
+		}
+		if s := suggest(key.Key, key.Accessed); s != "" {
+			d.Put(fmt.Errorf("unknown property %s.%s (did you mean %s?)", typ, key.Address, s))
+		} else {
+			d.Put(fmt.Errorf("unknown property %s.%s", typ, key.Address))
+		}
+	}
+}
+
+// addresses collects the addresses of the keys in v, in the same format as dproxy.UnvisitedKey.
+func addresses(addr string, v any, ret map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if addr != "" {
+				k = addr + "." + k
+			}
+			ret[k] = true
+			addresses(k, child, ret)
+		}
+	case []any:
+		for i, item := range v {
+			addresses(addr+"["+strconv.Itoa(i)+"]", item, ret)
+		}
+	}
+}
+
+// suggest returns the candidate that is the most similar to the key.
+// It returns an empty string if no candidates are similar enough.
+func suggest(key string, candidates []string) string {
+	var ret string
+	best := max(2, len(key)/4) + 1
+	for _, c := range candidates {
+		if dist := levenshtein(strings.ToLower(key), strings.ToLower(c)); dist < best {
+			best = dist
+			ret = c
+		}
+	}
+	return ret
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
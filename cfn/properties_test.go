@@ -0,0 +1,154 @@
+package cfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/dproxy"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func TestUnknownProperties(t *testing.T) {
+	tests := []struct {
+		name       string
+		typ        string
+		properties map[string]any
+		want       string
+	}{
+		{
+			name: "monitor",
+			typ:  "Custom::Monitor",
+			properties: map[string]any{
+				"ServiceToken":           "arn:aws:lambda:ap-northeast-1:1234567890:function:cfn-mackerel-macro",
+				"Type":                   "connectivity",
+				"Name":                   "foo-bar",
+				"NotificationIntervel":   60,
+				"ForceOwnership":         true,
+				"SomethingCompletelyNew": "value",
+			},
+			want: "unknown property Monitor.NotificationIntervel (did you mean NotificationInterval?); " +
+				"unknown property Monitor.SomethingCompletelyNew",
+		},
+		{
+			name: "dashboard widget",
+			typ:  "Custom::Dashboard",
+			properties: map[string]any{
+				"Title":   "dashboard-foobar",
+				"UrlPath": "my-dashboard",
+				"Widgets": []any{
+					map[string]any{
+						"Type":     "markdown",
+						"Title":    "Markdown",
+						"Markdown": "# Some Awesome Service",
+						"Layout": map[string]any{
+							"X":      "0",
+							"Y":      "0",
+							"Width":  "24",
+							"Hieght": "32",
+						},
+					},
+				},
+			},
+			want: "not found: Widgets[0].Layout.Height; " +
+				"unknown property Dashboard.Widgets[0].Layout.Hieght (did you mean Height?)",
+		},
+		{
+			name: "user",
+			typ:  "Custom::User",
+			properties: map[string]any{
+				"Email":    "foo@example.com",
+				"Authorty": "viewer",
+			},
+			want: "unknown property User.Authorty (did you mean Authority?)",
+		},
+		{
+			name: "adopt of service",
+			typ:  "Custom::Service",
+			properties: map[string]any{
+				"Name":  "awesome-service",
+				"Adopt": true,
+			},
+			want: "unknown property Service.Adopt",
+		},
+		{
+			name: "force ownership of graph annotation",
+			typ:  "Custom::GraphAnnotation",
+			properties: map[string]any{
+				"Service":        "mkr:test-org:service:awesome-service",
+				"Title":          "deploy",
+				"From":           1484000000,
+				"To":             1484000000,
+				"ForceOwnership": true,
+			},
+			want: "unknown property GraphAnnotation.ForceOwnership",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Function{
+				org: &mackerel.Org{
+					Name: "test-org",
+				},
+				// the converters must fail before calling the API.
+				client: &fakeMackerelClient{},
+			}
+			event := cfn.Event{
+				RequestType:        cfn.RequestCreate,
+				ResourceType:       tt.typ,
+				LogicalResourceID:  "Resource",
+				StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+				ResourceProperties: tt.properties,
+			}
+			_, _, err := f.Handle(context.Background(), event)
+			if err == nil {
+				t.Fatal("want error, got nil")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("unexpected error: want %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestCheckUnknownProperties_requestType(t *testing.T) {
+	check := func(event cfn.Event) error {
+		var d dproxy.Drain
+		in, tracker := dproxy.Track(event.ResourceProperties)
+		d.String(in.M("Name"))
+		checkUnknownProperties(&d, event, tracker)
+		return d.CombineErrors()
+	}
+
+	// the unknown properties are not reported on deletion.
+	err := check(cfn.Event{
+		RequestType:  cfn.RequestDelete,
+		ResourceType: "Custom::Service",
+		ResourceProperties: map[string]any{
+			"Name":  "awesome-service",
+			"Roles": []any{},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error on deletion: %v", err)
+	}
+
+	// the unknown properties that already exist are allowed on updates, e.g. rollbacks.
+	err = check(cfn.Event{
+		RequestType:  cfn.RequestUpdate,
+		ResourceType: "Custom::Service",
+		OldResourceProperties: map[string]any{
+			"Name":  "awesome-service",
+			"Roles": []any{},
+		},
+		ResourceProperties: map[string]any{
+			"Name":  "awesome-service",
+			"Roles": []any{},
+			"Mem":   "new property",
+		},
+	})
+	if err == nil || err.Error() != "unknown property Service.Mem" {
+		t.Errorf("unexpected error on update: %v", err)
+	}
+}
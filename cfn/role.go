@@ -19,9 +19,11 @@ type role struct {
 
 func (r *role) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	var d dproxy.Drain
-	in := dproxy.New(r.Event.ResourceProperties)
+	in, tracker := dproxy.Track(r.Event.ResourceProperties)
 	name := d.String(in.M("Name"))
 	service := d.String(in.M("Service"))
+	memo := d.String(dproxy.Default(in.M("Memo"), ""))
+	checkUnknownProperties(&d, r.Event, tracker)
 	err = d.CombineErrors()
 	if err != nil {
 		return
@@ -36,6 +38,7 @@ func (r *role) create(ctx context.Context) (physicalResourceID string, data map[
 	c := r.Function.getclient()
 	_, err = c.CreateRole(ctx, serviceName, &mackerel.CreateRoleParam{
 		Name: name,
+		Memo: memo,
 	})
 	if err != nil {
 		merr, ok := err.(mackerel.Error)
@@ -46,7 +49,15 @@ func (r *role) create(ctx context.Context) (physicalResourceID string, data map[
 			return "", nil, err
 		}
 
-		// the role may already exist. try to override it.
+		// the role may already exist. try to override it if it is owned by this stack.
+		if err := r.Function.checkRoleOwner(ctx, r.Event, serviceName, name); err != nil {
+			return "", nil, err
+		}
+
+		// the existing role may have another memo.
+		if _, err := c.UpdateRole(ctx, serviceName, name, &mackerel.UpdateRoleParam{Memo: memo}); err != nil {
+			return "", nil, err
+		}
 	}
 	creationErr := err
 
@@ -65,38 +76,59 @@ func (r *role) create(ctx context.Context) (physicalResourceID string, data map[
 	data = map[string]any{
 		"Name":     name,
 		"FullName": serviceName + ":" + name,
+		"Memo":     memo,
 	}
 	return
 }
 
 func (r *role) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	var d dproxy.Drain
-	in := dproxy.New(r.Event.ResourceProperties)
+	in, tracker := dproxy.Track(r.Event.ResourceProperties)
 	old := dproxy.New(r.Event.OldResourceProperties)
 
 	name := d.String(in.M("Name"))
 	service := d.String(in.M("Service"))
+	memo := d.String(dproxy.Default(in.M("Memo"), ""))
 	oldName := d.String(old.M("Name"))
 	oldService := d.String(old.M("Service"))
+	oldMemo := d.String(dproxy.Default(old.M("Memo"), ""))
+	checkUnknownProperties(&d, r.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return "", nil, err
 	}
 
+	if name != oldName || service != oldService {
+		// Mackerel doesn't support renaming roles or moving them to another service.
+		// need to create a new role, and CloudFormation deletes the old one.
+		return r.create(ctx)
+	}
+
 	serviceName, err := r.Function.parseServiceID(ctx, service)
 	if err != nil {
 		err = fmt.Errorf("failed to parse %q as service id: %s", service, err)
 		return
 	}
-	if name == oldName && service == oldService {
-		// No update is needed.
-		return r.Event.PhysicalResourceID, map[string]any{
-			"Name":     name,
-			"FullName": serviceName + ":" + name,
-		}, nil
+	if err := r.Function.checkRoleOwner(ctx, r.Event, serviceName, name); err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+	c := r.Function.getclient()
+	if memo != oldMemo {
+		if _, err := c.UpdateRole(ctx, serviceName, name, &mackerel.UpdateRoleParam{Memo: memo}); err != nil {
+			return r.Event.PhysicalResourceID, nil, err
+		}
 	}
 
-	// need to create a new role.
-	return r.create(ctx)
+	// the owner may be changed by ForceOwnership.
+	meta := getmetadata(r.Event)
+	if err := c.PutRoleMetaData(ctx, serviceName, name, "cloudformation", meta); err != nil {
+		return r.Event.PhysicalResourceID, nil, err
+	}
+
+	return r.Event.PhysicalResourceID, map[string]any{
+		"Name":     name,
+		"FullName": serviceName + ":" + name,
+		"Memo":     memo,
+	}, nil
 }
 
 func (r *role) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
@@ -108,6 +140,10 @@ func (r *role) delete(ctx context.Context) (physicalResourceID string, data map[
 		return
 	}
 
+	if err = r.Function.checkRoleOwner(ctx, r.Event, serviceName, roleName); err != nil {
+		return
+	}
+
 	c := r.Function.getclient()
 	_, err = c.DeleteRole(ctx, serviceName, roleName)
 	var merr mackerel.Error
@@ -80,18 +80,32 @@ func TestCreateRole(t *testing.T) {
 }
 
 func TestCreateRole_AlreadyExists(t *testing.T) {
+	var updated bool
 	r := &role{
 		Function: &Function{
 			org: &mackerel.Org{
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					// the role is created by the same stack.
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.RoleMetaMetaData{}, nil
+				},
 				createRole: func(ctx context.Context, serviceName string, param *mackerel.CreateRoleParam) (*mackerel.Role, error) {
 					// The role is already created. This error should be ignored.
 					return nil, mkrError{
 						statusCode: http.StatusBadRequest,
 					}
 				},
+				updateRole: func(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error) {
+					// the memo of the existing role is updated.
+					updated = true
+					return &mackerel.Role{
+						Name: roleName,
+						Memo: param.Memo,
+					}, nil
+				},
 				putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
 					if namespace != "cloudformation" {
 						t.Errorf("unexpected namespace: want cloudformation, got %s", namespace)
@@ -141,6 +155,173 @@ func TestCreateRole_AlreadyExists(t *testing.T) {
 	if param["FullName"].(string) != "awesome-service:role-app" {
 		t.Errorf("unexpected name, want %s, got %s", "awesome-service:role-app", param["FullName"].(string))
 	}
+	if !updated {
+		t.Error("the role is not updated")
+	}
+}
+
+func TestUpdateRole_memo(t *testing.T) {
+	var updated bool
+	r := &role{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.RoleMetaMetaData{}, nil
+				},
+				updateRole: func(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error) {
+					updated = true
+					if serviceName != "awesome-service" {
+						t.Errorf("unexpected service name, want %s, got %s", "awesome-service", serviceName)
+					}
+					if roleName != "role-app" {
+						t.Errorf("unexpected role name, want %s, got %s", "role-app", roleName)
+					}
+					if param.Memo != "new memo" {
+						t.Errorf("unexpected memo, want %s, got %s", "new memo", param.Memo)
+					}
+					return &mackerel.Role{
+						Name: roleName,
+						Memo: param.Memo,
+					}, nil
+				},
+				putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom:Role",
+			LogicalResourceID:  "Role",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:role:awesome-service:role-app",
+			OldResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:awesome-service",
+				"Name":    "role-app",
+				"Memo":    "old memo",
+			},
+			ResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:awesome-service",
+				"Name":    "role-app",
+				"Memo":    "new memo",
+			},
+		},
+	}
+	id, param, err := r.update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:role:awesome-service:role-app" {
+		t.Errorf("unexpected role id: want %s, got %s", "mkr:test-org:role:awesome-service:role-app", id)
+	}
+	if param["Memo"].(string) != "new memo" {
+		t.Errorf("unexpected memo, want %s, got %s", "new memo", param["Memo"].(string))
+	}
+	if !updated {
+		t.Error("the role is not updated")
+	}
+}
+
+func TestUpdateRole_forceOwnership(t *testing.T) {
+	var owner string
+	r := &role{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.RoleMetaMetaData{}, nil
+				},
+				updateRole: func(ctx context.Context, serviceName, roleName string, param *mackerel.UpdateRoleParam) (*mackerel.Role, error) {
+					t.Error("the memo is not changed, the role should not be updated")
+					return nil, nil
+				},
+				putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
+					owner = v.(metadata).StackID
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom::Role",
+			LogicalResourceID:  "Role",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:role:awesome-service:role-app",
+			OldResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:awesome-service",
+				"Name":    "role-app",
+			},
+			ResourceProperties: map[string]any{
+				"Service":        "mkr:test-org:service:awesome-service",
+				"Name":           "role-app",
+				"ForceOwnership": true,
+			},
+		},
+	}
+	if _, _, err := r.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if owner != r.Event.StackID {
+		t.Errorf("unexpected owner: want %s, got %s", r.Event.StackID, owner)
+	}
+}
+
+func TestUpdateRole_changeService(t *testing.T) {
+	var created bool
+	r := &role{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createRole: func(ctx context.Context, serviceName string, param *mackerel.CreateRoleParam) (*mackerel.Role, error) {
+					created = true
+					if serviceName != "new-service" {
+						t.Errorf("unexpected service name, want %s, got %s", "new-service", serviceName)
+					}
+					return &mackerel.Role{
+						Name: param.Name,
+						Memo: param.Memo,
+					}, nil
+				},
+				putRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) error {
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom:Role",
+			LogicalResourceID:  "Role",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:role:old-service:role-app",
+			OldResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:old-service",
+				"Name":    "role-app",
+			},
+			ResourceProperties: map[string]any{
+				"Service": "mkr:test-org:service:new-service",
+				"Name":    "role-app",
+			},
+		},
+	}
+	id, _, err := r.update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:role:new-service:role-app" {
+		t.Errorf("unexpected role id: want %s, got %s", "mkr:test-org:role:new-service:role-app", id)
+	}
+	if !created {
+		t.Error("the role is not created")
+	}
 }
 
 func TestDeleteRole(t *testing.T) {
@@ -151,6 +332,11 @@ func TestDeleteRole(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				deleteRole: func(ctx context.Context, serviceName, roleName string) (*mackerel.Role, error) {
 					deleted = true
 					if serviceName != "awesome-service" {
@@ -196,6 +382,11 @@ func TestDeleteRole_RoleNotFound(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getRoleMetaData: func(ctx context.Context, serviceName, roleName, namespace string, v any) (*mackerel.RoleMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				deleteRole: func(ctx context.Context, serviceName, roleName string) (*mackerel.Role, error) {
 					deleted = true
 					if serviceName != "awesome-service" {
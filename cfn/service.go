@@ -17,16 +17,19 @@ type service struct {
 }
 
 func (s *service) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
-	in := dproxy.New(s.Event.ResourceProperties)
-	name, err := in.M("Name").String()
-	if err != nil {
+	var d dproxy.Drain
+	in, tracker := dproxy.Track(s.Event.ResourceProperties)
+	name := d.String(in.M("Name"))
+	memo := d.String(dproxy.Default(in.M("Memo"), ""))
+	checkUnknownProperties(&d, s.Event, tracker)
+	if err := d.CombineErrors(); err != nil {
 		return "", nil, err
 	}
 
 	c := s.Function.getclient()
 	_, err = c.CreateService(ctx, &mackerel.CreateServiceParam{
 		Name: name,
-		// TODO: memo
+		Memo: memo,
 	})
 	if err != nil {
 		merr, ok := err.(mackerel.Error)
@@ -37,7 +40,15 @@ func (s *service) create(ctx context.Context) (physicalResourceID string, data m
 			return "", nil, err
 		}
 
-		// the service may already exist. try to continue.
+		// the service may already exist. try to continue if it is owned by this stack.
+		if err := s.Function.checkServiceOwner(ctx, s.Event, name); err != nil {
+			return "", nil, err
+		}
+
+		// the existing service may have another memo.
+		if _, err := c.UpdateService(ctx, name, &mackerel.UpdateServiceParam{Memo: memo}); err != nil {
+			return "", nil, err
+		}
 	}
 	creationErr := err
 
@@ -56,29 +67,50 @@ func (s *service) create(ctx context.Context) (physicalResourceID string, data m
 
 	return id, map[string]any{
 		"Name": name,
+		"Memo": memo,
 	}, nil
 }
 
 func (s *service) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	var d dproxy.Drain
-	in := dproxy.New(s.Event.ResourceProperties)
+	in, tracker := dproxy.Track(s.Event.ResourceProperties)
 	old := dproxy.New(s.Event.OldResourceProperties)
 
 	name := d.String(in.M("Name"))
+	memo := d.String(dproxy.Default(in.M("Memo"), ""))
 	oldName := d.String(old.M("Name"))
+	oldMemo := d.String(dproxy.Default(old.M("Memo"), ""))
+	checkUnknownProperties(&d, s.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return "", nil, err
 	}
 
-	if name == oldName {
-		// No update is needed.
-		return s.Event.PhysicalResourceID, map[string]any{
-			"Name": name,
-		}, nil
+	if name != oldName {
+		// Mackerel doesn't support renaming services.
+		// need to create a new service, and CloudFormation deletes the old one.
+		return s.create(ctx)
 	}
 
-	// need to create a new service.
-	return s.create(ctx)
+	if err := s.Function.checkServiceOwner(ctx, s.Event, name); err != nil {
+		return s.Event.PhysicalResourceID, nil, err
+	}
+	c := s.Function.getclient()
+	if memo != oldMemo {
+		if _, err := c.UpdateService(ctx, name, &mackerel.UpdateServiceParam{Memo: memo}); err != nil {
+			return s.Event.PhysicalResourceID, nil, err
+		}
+	}
+
+	// the owner may be changed by ForceOwnership.
+	meta := getmetadata(s.Event)
+	if err := c.PutServiceMetaData(ctx, name, "cloudformation", meta); err != nil {
+		return s.Event.PhysicalResourceID, nil, err
+	}
+
+	return s.Event.PhysicalResourceID, map[string]any{
+		"Name": name,
+		"Memo": memo,
+	}, nil
 }
 
 func (s *service) delete(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
@@ -90,6 +122,10 @@ func (s *service) delete(ctx context.Context) (physicalResourceID string, data m
 		return
 	}
 
+	if err = s.Function.checkServiceOwner(ctx, s.Event, serviceName); err != nil {
+		return
+	}
+
 	c := s.Function.getclient()
 	_, err = c.DeleteService(ctx, serviceName)
 	var merr mackerel.Error
@@ -21,9 +21,12 @@ func TestCreateService(t *testing.T) {
 					if param.Name != "awesome-service" {
 						t.Errorf("unexpected name, want %s, got %s", "awesome-service", param.Name)
 					}
+					if param.Memo != "awesome memo" {
+						t.Errorf("unexpected memo, want %s, got %s", "awesome memo", param.Memo)
+					}
 					return &mackerel.Service{
 						Name:  param.Name,
-						Memo:  "",
+						Memo:  param.Memo,
 						Roles: []string{},
 					}, nil
 				},
@@ -56,6 +59,7 @@ func TestCreateService(t *testing.T) {
 			StackID:           "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
 			ResourceProperties: map[string]any{
 				"Name": "awesome-service",
+				"Memo": "awesome memo",
 			},
 		},
 	}
@@ -69,20 +73,37 @@ func TestCreateService(t *testing.T) {
 	if param["Name"].(string) != "awesome-service" {
 		t.Errorf("unexpected name, want %s, got %s", "awesome-service", param["Name"].(string))
 	}
+	if param["Memo"].(string) != "awesome memo" {
+		t.Errorf("unexpected memo, want %s, got %s", "awesome memo", param["Memo"].(string))
+	}
 }
 
 func TestCreateService_AlreadyExists(t *testing.T) {
+	var updated bool
 	s := &service{
 		Function: &Function{
 			org: &mackerel.Org{
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					// the service is created by the same stack.
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.ServiceMetaMetaData{}, nil
+				},
 				createService: func(ctx context.Context, param *mackerel.CreateServiceParam) (*mackerel.Service, error) {
 					return nil, mkrError{
 						statusCode: http.StatusBadRequest,
 					}
 				},
+				updateService: func(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error) {
+					// the memo of the existing service is updated.
+					updated = true
+					return &mackerel.Service{
+						Name: serviceName,
+						Memo: param.Memo,
+					}, nil
+				},
 				putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
 					if namespace != "cloudformation" {
 						t.Errorf("unexpected namespace: want cloudformation, got %s", namespace)
@@ -125,6 +146,174 @@ func TestCreateService_AlreadyExists(t *testing.T) {
 	if param["Name"].(string) != "awesome-service" {
 		t.Errorf("unexpected name, want %s, got %s", "awesome-service", param["Name"].(string))
 	}
+	if !updated {
+		t.Error("the service is not updated")
+	}
+}
+
+func TestUpdateService_memo(t *testing.T) {
+	var updated bool
+	s := &service{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.ServiceMetaMetaData{}, nil
+				},
+				updateService: func(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error) {
+					updated = true
+					if serviceName != "awesome-service" {
+						t.Errorf("unexpected name, want %s, got %s", "awesome-service", serviceName)
+					}
+					if param.Memo != "new memo" {
+						t.Errorf("unexpected memo, want %s, got %s", "new memo", param.Memo)
+					}
+					return &mackerel.Service{
+						Name: serviceName,
+						Memo: param.Memo,
+					}, nil
+				},
+				putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom:Service",
+			LogicalResourceID:  "Service",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:service:awesome-service",
+			OldResourceProperties: map[string]any{
+				"Name": "awesome-service",
+				"Memo": "old memo",
+			},
+			ResourceProperties: map[string]any{
+				"Name": "awesome-service",
+				"Memo": "new memo",
+			},
+		},
+	}
+	id, param, err := s.update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:service:awesome-service" {
+		t.Errorf("unexpected service id: want %s, got %s", "mkr:test-org:service:awesome-service", id)
+	}
+	if param["Memo"].(string) != "new memo" {
+		t.Errorf("unexpected memo, want %s, got %s", "new memo", param["Memo"].(string))
+	}
+	if !updated {
+		t.Error("the service is not updated")
+	}
+}
+
+func TestUpdateService_forceOwnership(t *testing.T) {
+	var owner string
+	s := &service{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					v.(*metadata).StackID = "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/other/12345678-1234-1234-1234-123456789abc"
+					return &mackerel.ServiceMetaMetaData{}, nil
+				},
+				updateService: func(ctx context.Context, serviceName string, param *mackerel.UpdateServiceParam) (*mackerel.Service, error) {
+					t.Error("the memo is not changed, the service should not be updated")
+					return nil, nil
+				},
+				putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+					owner = v.(metadata).StackID
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom::Service",
+			LogicalResourceID:  "Service",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:service:awesome-service",
+			OldResourceProperties: map[string]any{
+				"Name": "awesome-service",
+			},
+			ResourceProperties: map[string]any{
+				"Name":           "awesome-service",
+				"ForceOwnership": true,
+			},
+		},
+	}
+	if _, _, err := s.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if owner != s.Event.StackID {
+		t.Errorf("unexpected owner: want %s, got %s", s.Event.StackID, owner)
+	}
+
+	// the owner is checked even if nothing is changed.
+	s.Event.ResourceProperties = map[string]any{
+		"Name": "awesome-service",
+	}
+	if _, _, err := s.update(context.Background()); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestUpdateService_rename(t *testing.T) {
+	var created bool
+	s := &service{
+		Function: &Function{
+			org: &mackerel.Org{
+				Name: "test-org",
+			},
+			client: &fakeMackerelClient{
+				createService: func(ctx context.Context, param *mackerel.CreateServiceParam) (*mackerel.Service, error) {
+					created = true
+					if param.Name != "new-service" {
+						t.Errorf("unexpected name, want %s, got %s", "new-service", param.Name)
+					}
+					return &mackerel.Service{
+						Name: param.Name,
+						Memo: param.Memo,
+					}, nil
+				},
+				putServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) error {
+					return nil
+				},
+			},
+		},
+		Event: cfn.Event{
+			RequestType:        cfn.RequestUpdate,
+			ResourceType:       "Custom:Service",
+			LogicalResourceID:  "Service",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			PhysicalResourceID: "mkr:test-org:service:old-service",
+			OldResourceProperties: map[string]any{
+				"Name": "old-service",
+				"Memo": "old memo",
+			},
+			ResourceProperties: map[string]any{
+				"Name": "new-service",
+				"Memo": "new memo",
+			},
+		},
+	}
+	id, _, err := s.update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "mkr:test-org:service:new-service" {
+		t.Errorf("unexpected service id: want %s, got %s", "mkr:test-org:service:new-service", id)
+	}
+	if !created {
+		t.Error("the service is not created")
+	}
 }
 
 func TestDeleteService(t *testing.T) {
@@ -135,6 +324,11 @@ func TestDeleteService(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				deleteService: func(ctx context.Context, serviceName string) (*mackerel.Service, error) {
 					deleted = true
 					if serviceName != "awesome-service" {
@@ -176,6 +370,11 @@ func TestDeleteService_serviceNotFound(t *testing.T) {
 				Name: "test-org",
 			},
 			client: &fakeMackerelClient{
+				getServiceMetaData: func(ctx context.Context, serviceName, namespace string, v any) (*mackerel.ServiceMetaMetaData, error) {
+					return nil, mkrError{
+						statusCode: http.StatusNotFound,
+					}
+				},
 				deleteService: func(ctx context.Context, serviceName string) (*mackerel.Service, error) {
 					deleted = true
 					if serviceName != "awesome-service" {
@@ -19,10 +19,11 @@ type user struct {
 
 func (u *user) create(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	var d dproxy.Drain
-	in := dproxy.New(u.Event.ResourceProperties)
+	in, tracker := dproxy.Track(u.Event.ResourceProperties)
 
 	email := d.String(in.M("Email"))
 	authority := d.String(dproxy.Default(in.M("Authority"), "viewer"))
+	checkUnknownProperties(&d, u.Event, tracker)
 	err = d.CombineErrors()
 	if err != nil {
 		return
@@ -96,11 +97,13 @@ func (u *user) getUserID(ctx context.Context, email string) (string, error) {
 
 func (u *user) update(ctx context.Context) (physicalResourceID string, data map[string]any, err error) {
 	var d dproxy.Drain
-	in := dproxy.New(u.Event.ResourceProperties)
+	in, tracker := dproxy.Track(u.Event.ResourceProperties)
 	old := dproxy.New(u.Event.OldResourceProperties)
 
 	email := d.String(in.M("Email"))
 	oldEmail := d.String(old.M("Email"))
+	d.String(dproxy.Default(in.M("Authority"), "viewer")) // validated only, updating authority is not supported.
+	checkUnknownProperties(&d, u.Event, tracker)
 	if err := d.CombineErrors(); err != nil {
 		return u.Event.PhysicalResourceID, nil, err
 	}
@@ -0,0 +1,182 @@
+// Command cfn-mackerel-drift detects drift of the Mackerel resources managed by the macro.
+//
+// CloudFormation drift detection does not support custom resources,
+// so the changes in the Mackerel UI go unnoticed.
+// This command compares the live Mackerel objects with the template.
+//
+// Usage:
+//
+//	aws cloudformation get-template --template-stage Processed --stack-name STACK > template.json
+//	aws cloudformation describe-stack-resources --stack-name STACK > resources.json
+//	MACKEREL_APIKEY=... cfn-mackerel-drift -template template.json -resources resources.json
+//
+// The resources whose conditions are false are ignored.
+// The resources that refer to the attributes of other resources by Fn::GetAtt can't be checked,
+// and they are reported as NOT_CHECKED with a warning.
+//
+// The result is written to the standard output in JSON format.
+// The exit status is 2 if some resources are drifted, and 1 if some errors occur.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	lambdacfn "github.com/aws/aws-lambda-go/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/cfn"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey/aws"
+)
+
+type parameterFlags map[string]string
+
+func (p parameterFlags) String() string {
+	return fmt.Sprint(map[string]string(p))
+}
+
+func (p parameterFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid parameter %q, it should be Name=Value", s)
+	}
+	p[name] = value
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cfn-mackerel-drift: ")
+
+	var templateFile, resourcesFile string
+	parameters := parameterFlags{}
+	flag.StringVar(&templateFile, "template", "", "the processed template in JSON format")
+	flag.StringVar(&resourcesFile, "resources", "", "the output of `aws cloudformation describe-stack-resources`")
+	flag.Var(parameters, "parameter", "the parameter of the stack in `Name=Value` format; it can be specified multiple times")
+	flag.Parse()
+	if templateFile == "" || resourcesFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	os.Exit(run(context.Background(), templateFile, resourcesFile, parameters))
+}
+
+func run(ctx context.Context, templateFile, resourcesFile string, parameters map[string]string) int {
+	tmpl, err := loadTemplate(templateFile)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	resources, err := loadStackResources(resourcesFile)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	provider, err := aws.LoadDefaultProvider(ctx)
+	if err != nil {
+		log.Printf("failed to load aws config: %v", err)
+		return 1
+	}
+	resolver, err := aws.LoadDefaultResolver(ctx)
+	if err != nil {
+		log.Printf("failed to load aws config: %v", err)
+		return 1
+	}
+	f := &cfn.Function{
+		APIKeyProvider: provider,
+		APIKeyResolver: resolver,
+	}
+	if base := os.Getenv("MACKEREL_APIURL"); base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			log.Printf("failed to parse base url: %v", err)
+			return 1
+		}
+		f.BaseURL = u
+	}
+
+	status := 0
+	results := []*cfn.ResourceDrift{}
+	r := newResolver(tmpl, resources, parameters)
+	for _, logicalID := range sortedKeys(tmpl.Resources) {
+		res := tmpl.Resources[logicalID]
+		if !strings.HasPrefix(res.Type, "Custom::") && !strings.HasPrefix(res.Type, "Mackerel::") {
+			continue
+		}
+		if res.Condition != "" {
+			ok, err := r.condition(res.Condition)
+			if err != nil {
+				log.Printf("failed to evaluate the condition of %s: %v", logicalID, err)
+				status = 1
+				continue
+			}
+			if !ok {
+				// the resource is not created.
+				continue
+			}
+		}
+		event, err := buildEvent(r, logicalID, res.Type, res.Properties, resources)
+		var skip *skipError
+		if errors.As(err, &skip) {
+			log.Printf("warning: skip %s: %v", logicalID, err)
+			results = append(results, &cfn.ResourceDrift{
+				LogicalResourceID:  logicalID,
+				PhysicalResourceID: event.PhysicalResourceID,
+				ResourceType:       res.Type,
+				DriftStatus:        cfn.DriftStatusNotChecked,
+			})
+			continue
+		}
+		if err != nil {
+			log.Print(err)
+			status = 1
+			continue
+		}
+		drift, err := f.DetectDrift(ctx, event)
+		if err != nil {
+			log.Print(err)
+			status = 1
+			continue
+		}
+		if status == 0 && (drift.DriftStatus == cfn.DriftStatusModified || drift.DriftStatus == cfn.DriftStatusDeleted) {
+			status = 2
+		}
+		results = append(results, drift)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		log.Print(err)
+		return 1
+	}
+	return status
+}
+
+// buildEvent builds a pseudo event of the custom resource for drift detection.
+func buildEvent(r *resolver, logicalID, typ string, properties map[string]any, resources []stackResource) (lambdacfn.Event, error) {
+	for _, res := range resources {
+		if res.LogicalResourceID != logicalID {
+			continue
+		}
+		resolved, err := r.resolveProperties(properties)
+		if err != nil {
+			// PhysicalResourceID is returned for reporting the skipped resource.
+			return lambdacfn.Event{PhysicalResourceID: res.PhysicalResourceID}, fmt.Errorf("failed to resolve the properties of %s: %w", logicalID, err)
+		}
+		return lambdacfn.Event{
+			ResourceType:       typ,
+			LogicalResourceID:  logicalID,
+			PhysicalResourceID: res.PhysicalResourceID,
+			StackID:            res.StackID,
+			ResourceProperties: resolved,
+		}, nil
+	}
+	return lambdacfn.Event{}, fmt.Errorf("%s is not found in the stack resources", logicalID)
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// template is a CloudFormation template in JSON format.
+type template struct {
+	Parameters map[string]struct {
+		Default any `json:"Default"`
+	} `json:"Parameters"`
+	Conditions map[string]any `json:"Conditions"`
+	Resources  map[string]struct {
+		Type       string         `json:"Type"`
+		Condition  string         `json:"Condition"`
+		Properties map[string]any `json:"Properties"`
+	} `json:"Resources"`
+}
+
+// stackResource is an item of the output of `aws cloudformation describe-stack-resources`.
+type stackResource struct {
+	StackName          string `json:"StackName"`
+	StackID            string `json:"StackId"`
+	LogicalResourceID  string `json:"LogicalResourceId"`
+	PhysicalResourceID string `json:"PhysicalResourceId"`
+	ResourceType       string `json:"ResourceType"`
+}
+
+// loadTemplate loads the template.
+// It accepts both the template itself and the output of `aws cloudformation get-template`.
+func loadTemplate(name string) (*template, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		TemplateBody json.RawMessage `json:"TemplateBody"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if len(body.TemplateBody) > 0 {
+		data = body.TemplateBody
+		var s string
+		if err := json.Unmarshal(data, &s); err == nil {
+			// the template body is a string.
+			data = []byte(s)
+		}
+	}
+
+	var tmpl template
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to parse %s, only JSON templates are supported: %w", name, err)
+	}
+	return &tmpl, nil
+}
+
+// loadStackResources loads the output of `aws cloudformation describe-stack-resources`.
+func loadStackResources(name string) ([]stackResource, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		StackResources []stackResource `json:"StackResources"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return out.StackResources, nil
+}
+
+// noValue is the value of AWS::NoValue.
+var noValue = &struct{}{}
+
+// skipError means that the resource can't be checked, and it should be skipped.
+// e.g. the attributes of resources are not available in the output of `aws cloudformation describe-stack-resources`.
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// resolver resolves the intrinsic functions in the template.
+type resolver struct {
+	stackName  string
+	stackID    string
+	parameters map[string]any
+	physicalID map[string]string
+	conditions map[string]any
+	evaluated  map[string]bool
+}
+
+func newResolver(tmpl *template, resources []stackResource, parameters map[string]string) *resolver {
+	r := &resolver{
+		parameters: make(map[string]any),
+		physicalID: make(map[string]string),
+		conditions: tmpl.Conditions,
+		evaluated:  make(map[string]bool),
+	}
+	for name, p := range tmpl.Parameters {
+		if p.Default != nil {
+			r.parameters[name] = p.Default
+		}
+	}
+	for name, v := range parameters {
+		r.parameters[name] = v
+	}
+	for _, res := range resources {
+		r.stackName = res.StackName
+		r.stackID = res.StackID
+		r.physicalID[res.LogicalResourceID] = res.PhysicalResourceID
+	}
+	return r
+}
+
+// resolveProperties resolves the intrinsic functions in the properties.
+// The scalar values are converted into strings,
+// because CloudFormation passes the properties of custom resources as strings.
+func (r *resolver) resolveProperties(properties map[string]any) (map[string]any, error) {
+	v, err := r.resolve(properties)
+	if err != nil {
+		return nil, err
+	}
+	if v == noValue {
+		return map[string]any{}, nil
+	}
+	return v.(map[string]any), nil
+}
+
+func (r *resolver) resolve(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 1 {
+			for name, arg := range v {
+				if name == "Ref" || strings.HasPrefix(name, "Fn::") {
+					return r.resolveFunction(name, arg)
+				}
+			}
+		}
+		ret := make(map[string]any, len(v))
+		for key, value := range v {
+			resolved, err := r.resolve(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if resolved != noValue {
+				ret[key] = resolved
+			}
+		}
+		return ret, nil
+	case []any:
+		ret := make([]any, 0, len(v))
+		for i, value := range v {
+			resolved, err := r.resolve(value)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			if resolved != noValue {
+				ret = append(ret, resolved)
+			}
+		}
+		return ret, nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return noValue, nil
+	}
+	return nil, fmt.Errorf("unexpected value: %v", v)
+}
+
+func (r *resolver) resolveFunction(name string, arg any) (any, error) {
+	switch name {
+	case "Ref":
+		ref, ok := arg.(string)
+		if !ok {
+			return nil, errors.New("the argument of Ref should be a string")
+		}
+		return r.ref(ref)
+	case "Fn::Sub":
+		return r.sub(arg)
+	case "Fn::Join":
+		return r.join(arg)
+	case "Fn::Select":
+		return r.selectFunc(arg)
+	case "Fn::If":
+		return r.ifFunc(arg)
+	case "Fn::GetAtt":
+		return nil, &skipError{reason: "Fn::GetAtt can't be resolved, because the attributes of the resources are unknown"}
+	}
+	return nil, fmt.Errorf("%s is not supported", name)
+}
+
+func (r *resolver) ref(name string) (any, error) {
+	switch name {
+	case "AWS::StackName":
+		return r.stackName, nil
+	case "AWS::StackId":
+		return r.stackID, nil
+	case "AWS::Region", "AWS::AccountId", "AWS::Partition":
+		// arn format: arn:${AWS::Partition}:cloudformation:${AWS::Region}:${AWS::AccountId}:stack/${AWS::StackName}/${UUID}
+		arn := strings.Split(r.stackID, ":")
+		if len(arn) < 6 {
+			return nil, fmt.Errorf("failed to parse the stack id: %s", r.stackID)
+		}
+		switch name {
+		case "AWS::Partition":
+			return arn[1], nil
+		case "AWS::Region":
+			return arn[3], nil
+		default:
+			return arn[4], nil
+		}
+	case "AWS::NoValue":
+		return noValue, nil
+	}
+	if id, ok := r.physicalID[name]; ok {
+		return id, nil
+	}
+	if v, ok := r.parameters[name]; ok {
+		return r.resolve(v)
+	}
+	return nil, fmt.Errorf("unresolved reference: %s", name)
+}
+
+var subPattern = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+
+func (r *resolver) sub(arg any) (any, error) {
+	var format string
+	vars := map[string]any{}
+	switch arg := arg.(type) {
+	case string:
+		format = arg
+	case []any:
+		if len(arg) != 2 {
+			return nil, errors.New("Fn::Sub requires a string and a map")
+		}
+		s, ok := arg[0].(string)
+		if !ok {
+			return nil, errors.New("the first argument of Fn::Sub should be a string")
+		}
+		m, ok := arg[1].(map[string]any)
+		if !ok {
+			return nil, errors.New("the second argument of Fn::Sub should be a map")
+		}
+		format, vars = s, m
+	default:
+		return nil, errors.New("the argument of Fn::Sub should be a string or a list")
+	}
+
+	var err error
+	ret := subPattern.ReplaceAllStringFunc(format, func(s string) string {
+		name := s[2 : len(s)-1]
+		var v any
+		var e error
+		if value, ok := vars[name]; ok {
+			v, e = r.resolve(value)
+		} else {
+			v, e = r.ref(name)
+		}
+		if e != nil {
+			err = e
+			return ""
+		}
+		str, ok := v.(string)
+		if !ok {
+			err = fmt.Errorf("%s is not a string", name)
+		}
+		return str
+	})
+	if err != nil {
+		return nil, err
+	}
+	// ${!Literal} is written as ${Literal}
+	return strings.ReplaceAll(ret, "${!", "${"), nil
+}
+
+func (r *resolver) join(arg any) (any, error) {
+	args, ok := arg.([]any)
+	if !ok || len(args) != 2 {
+		return nil, errors.New("Fn::Join requires a delimiter and a list")
+	}
+	delimiter, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("the delimiter of Fn::Join should be a string")
+	}
+	list, err := r.resolve(args[1])
+	if err != nil {
+		return nil, err
+	}
+	items, ok := list.([]any)
+	if !ok {
+		return nil, errors.New("the second argument of Fn::Join should be a list")
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, errors.New("the items of Fn::Join should be strings")
+		}
+		values = append(values, s)
+	}
+	return strings.Join(values, delimiter), nil
+}
+
+func (r *resolver) selectFunc(arg any) (any, error) {
+	args, ok := arg.([]any)
+	if !ok || len(args) != 2 {
+		return nil, errors.New("Fn::Select requires an index and a list")
+	}
+	index, err := r.resolve(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s, _ := index.(string)
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index of Fn::Select: %v", args[0])
+	}
+	list, err := r.resolve(args[1])
+	if err != nil {
+		return nil, err
+	}
+	items, ok := list.([]any)
+	if !ok {
+		return nil, errors.New("the second argument of Fn::Select should be a list")
+	}
+	if i < 0 || i >= len(items) {
+		return nil, fmt.Errorf("the index of Fn::Select is out of range: %d", i)
+	}
+	return items[i], nil
+}
+
+func (r *resolver) ifFunc(arg any) (any, error) {
+	args, ok := arg.([]any)
+	if !ok || len(args) != 3 {
+		return nil, errors.New("Fn::If requires a condition name and two values")
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("the condition name of Fn::If should be a string")
+	}
+	cond, err := r.condition(name)
+	if err != nil {
+		return nil, err
+	}
+	if cond {
+		return r.resolve(args[1])
+	}
+	return r.resolve(args[2])
+}
+
+// condition evaluates the condition in the Conditions section.
+func (r *resolver) condition(name string) (bool, error) {
+	if v, ok := r.evaluated[name]; ok {
+		return v, nil
+	}
+	def, ok := r.conditions[name]
+	if !ok {
+		return false, fmt.Errorf("unresolved condition: %s", name)
+	}
+	v, err := r.evaluateCondition(def)
+	if err != nil {
+		return false, fmt.Errorf("condition %s: %w", name, err)
+	}
+	r.evaluated[name] = v
+	return v, nil
+}
+
+func (r *resolver) evaluateCondition(def any) (bool, error) {
+	m, ok := def.(map[string]any)
+	if !ok || len(m) != 1 {
+		return false, fmt.Errorf("invalid condition: %v", def)
+	}
+	var name string
+	var arg any
+	for k, v := range m {
+		name, arg = k, v
+	}
+
+	if name == "Condition" {
+		ref, ok := arg.(string)
+		if !ok {
+			return false, errors.New("the argument of Condition should be a string")
+		}
+		return r.condition(ref)
+	}
+
+	args, ok := arg.([]any)
+	if !ok {
+		return false, fmt.Errorf("the argument of %s should be a list", name)
+	}
+	switch name {
+	case "Fn::Equals":
+		if len(args) != 2 {
+			return false, errors.New("Fn::Equals requires two values")
+		}
+		a, err := r.resolve(args[0])
+		if err != nil {
+			return false, err
+		}
+		b, err := r.resolve(args[1])
+		if err != nil {
+			return false, err
+		}
+		return reflect.DeepEqual(a, b), nil
+	case "Fn::Not":
+		if len(args) != 1 {
+			return false, errors.New("Fn::Not requires a condition")
+		}
+		v, err := r.evaluateCondition(args[0])
+		return !v, err
+	case "Fn::And", "Fn::Or":
+		// Fn::And is false if any condition is false, and Fn::Or is true if any condition is true.
+		and := name == "Fn::And"
+		for _, cond := range args {
+			v, err := r.evaluateCondition(cond)
+			if err != nil {
+				return false, err
+			}
+			if v != and {
+				return v, nil
+			}
+		}
+		return and, nil
+	}
+	return false, fmt.Errorf("%s is not supported in conditions", name)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveProperties(t *testing.T) {
+	tmpl := &template{}
+	tmpl.Parameters = map[string]struct {
+		Default any `json:"Default"`
+	}{
+		"Env": {Default: "dev"},
+	}
+	resources := []stackResource{
+		{
+			StackName:          "foobar",
+			StackID:            "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+			LogicalResourceID:  "Service",
+			PhysicalResourceID: "mkr:test-org:service:awesome-service",
+		},
+	}
+	r := newResolver(tmpl, resources, map[string]string{"Env": "prod"})
+
+	got, err := r.resolveProperties(map[string]any{
+		"Type":   "connectivity",
+		"Name":   map[string]any{"Fn::Sub": "${AWS::StackName}-${Env} in ${AWS::Region}"},
+		"Memo":   map[string]any{"Fn::Join": []any{",", []any{"a", map[string]any{"Ref": "AWS::AccountId"}}}},
+		"Scopes": []any{map[string]any{"Ref": "Service"}, map[string]any{"Ref": "AWS::NoValue"}},
+		"Literal": map[string]any{
+			"Fn::Sub": []any{"${!Literal}-${Var}", map[string]any{"Var": "value"}},
+		},
+		"NotificationInterval": float64(60),
+		"IsMute":               true,
+		"ExcludeScopes":        map[string]any{"Ref": "AWS::NoValue"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"Type":                 "connectivity",
+		"Name":                 "foobar-prod in ap-northeast-1",
+		"Memo":                 "a,1234567890",
+		"Scopes":               []any{"mkr:test-org:service:awesome-service"},
+		"Literal":              "${Literal}-value",
+		"NotificationInterval": "60",
+		"IsMute":               "true",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("properties differ: (-got +want)\n%s", diff)
+	}
+}
+
+func TestResolveProperties_unsupported(t *testing.T) {
+	r := newResolver(&template{}, nil, nil)
+	// the resource that refers to attributes is skipped.
+	_, err := r.resolveProperties(map[string]any{
+		"RoleArn": map[string]any{"Fn::GetAtt": []any{"MackerelRole", "Arn"}},
+	})
+	var skip *skipError
+	if !errors.As(err, &skip) {
+		t.Errorf("want skipError, got %v", err)
+	}
+
+	_, err = r.resolveProperties(map[string]any{
+		"Service": map[string]any{"Ref": "Unknown"},
+	})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestResolveProperties_condition(t *testing.T) {
+	tmpl := &template{
+		Conditions: map[string]any{
+			"IsProd":    map[string]any{"Fn::Equals": []any{map[string]any{"Ref": "Env"}, "prod"}},
+			"IsDev":     map[string]any{"Fn::Not": []any{map[string]any{"Condition": "IsProd"}}},
+			"IsProdJP":  map[string]any{"Fn::And": []any{map[string]any{"Condition": "IsProd"}, map[string]any{"Fn::Equals": []any{map[string]any{"Ref": "AWS::Region"}, "ap-northeast-1"}}}},
+			"IsDevOrJP": map[string]any{"Fn::Or": []any{map[string]any{"Condition": "IsDev"}, map[string]any{"Condition": "IsProdJP"}}},
+		},
+	}
+	resources := []stackResource{
+		{
+			StackName: "foobar",
+			StackID:   "arn:aws:cloudformation:ap-northeast-1:1234567890:stack/foobar/12345678-1234-1234-1234-123456789abc",
+		},
+	}
+	r := newResolver(tmpl, resources, map[string]string{"Env": "prod"})
+
+	got, err := r.resolveProperties(map[string]any{
+		"Name":   map[string]any{"Fn::If": []any{"IsProd", "production", "development"}},
+		"Memo":   map[string]any{"Fn::If": []any{"IsDev", "development", map[string]any{"Ref": "AWS::NoValue"}}},
+		"Region": map[string]any{"Fn::If": []any{"IsDevOrJP", "jp", "other"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"Name":   "production",
+		"Region": "jp",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("properties differ: (-got +want)\n%s", diff)
+	}
+
+	if _, err := r.resolveProperties(map[string]any{
+		"Name": map[string]any{"Fn::If": []any{"Unknown", "a", "b"}},
+	}); err == nil {
+		t.Error("want error, got nil")
+	}
+}
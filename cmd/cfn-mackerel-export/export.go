@@ -0,0 +1,572 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// exportClient is the subset of the Mackerel API client used by the exporter.
+type exportClient interface {
+	GetOrg(ctx context.Context) (*mackerel.Org, error)
+	FindUsers(ctx context.Context) ([]*mackerel.User, error)
+	FindServices(ctx context.Context) ([]*mackerel.Service, error)
+	FindMonitors(ctx context.Context) ([]mackerel.Monitor, error)
+	FindNotificationChannels(ctx context.Context) ([]mackerel.NotificationChannel, error)
+	FindNotificationGroups(ctx context.Context) ([]*mackerel.NotificationGroup, error)
+	FindDowntimes(ctx context.Context) ([]*mackerel.Downtime, error)
+	FindDashboards(ctx context.Context) ([]*mackerel.Dashboard, error)
+	FindDashboard(ctx context.Context, dashboardID string) (*mackerel.Dashboard, error)
+	FindAWSIntegrations(ctx context.Context) ([]*mackerel.AWSIntegration, error)
+}
+
+// exporter builds a template from the objects in a Mackerel organization.
+type exporter struct {
+	client exportClient
+	org    string
+
+	// the name of the macro, which is configured by MacroName of the macro stack.
+	transform string
+
+	// the email addresses of the users, keyed by the user ids.
+	emails map[string]string
+
+	// the logical ids of the generated resources, keyed by the physical ids.
+	logicalIDs map[string]string
+
+	// the set of the logical ids in use.
+	used map[string]bool
+
+	parameters map[string]any
+	resources  map[string]any
+}
+
+type exportedObjects struct {
+	services     []*mackerel.Service
+	monitors     []mackerel.Monitor
+	channels     []mackerel.NotificationChannel
+	groups       []*mackerel.NotificationGroup
+	downtimes    []*mackerel.Downtime
+	dashboards   []*mackerel.Dashboard
+	integrations []*mackerel.AWSIntegration
+}
+
+func newExporter(client exportClient) *exporter {
+	return &exporter{
+		client:     client,
+		transform:  "Mackerel",
+		emails:     make(map[string]string),
+		logicalIDs: make(map[string]string),
+		used:       make(map[string]bool),
+		parameters: make(map[string]any),
+		resources:  make(map[string]any),
+	}
+}
+
+// export returns the template.
+func (e *exporter) export(ctx context.Context) (map[string]any, error) {
+	org, err := e.client.GetOrg(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the org: %w", err)
+	}
+	e.org = org.Name
+
+	users, err := e.client.FindUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	for _, u := range users {
+		e.emails[u.ID] = u.Email
+	}
+
+	objs, err := e.find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// assign the logical ids first,
+	// because the objects may refer to the objects that appear later.
+	for _, s := range objs.services {
+		e.assign("Service", s.Name, e.physicalID("service", s.Name))
+		for _, r := range s.Roles {
+			e.assign("Role", s.Name+" "+r, e.physicalID("role", s.Name, r))
+		}
+	}
+	for _, m := range objs.monitors {
+		e.assign("Monitor", m.MonitorName(), e.physicalID("monitor", m.MonitorID()))
+	}
+	for _, ch := range objs.channels {
+		e.assign("NotificationChannel", ch.NotificationChannelName(), e.physicalID("notification-channel", ch.NotificationChannelID()))
+	}
+	for _, g := range objs.groups {
+		e.assign("NotificationGroup", g.Name, e.physicalID("notification-group", g.ID))
+	}
+	for _, d := range objs.downtimes {
+		e.assign("Downtime", d.Name, e.physicalID("downtime", d.ID))
+	}
+	for _, d := range objs.dashboards {
+		e.assign("Dashboard", d.URLPath, e.physicalID("dashboard", d.ID))
+	}
+	for _, i := range objs.integrations {
+		e.assign("AWSIntegration", i.Name, e.physicalID("aws-integration", i.ID))
+	}
+
+	for _, s := range objs.services {
+		e.exportService(s)
+	}
+	for _, m := range objs.monitors {
+		if err := e.exportMonitor(m); err != nil {
+			return nil, err
+		}
+	}
+	for _, ch := range objs.channels {
+		if err := e.exportNotificationChannel(ch); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range objs.groups {
+		e.exportNotificationGroup(g)
+	}
+	for _, d := range objs.downtimes {
+		if err := e.exportDowntime(d); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range objs.dashboards {
+		if err := e.exportDashboard(d); err != nil {
+			return nil, err
+		}
+	}
+	for _, i := range objs.integrations {
+		e.exportAWSIntegration(i)
+	}
+
+	tmpl := map[string]any{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Transform":                e.transform,
+		"Description":              fmt.Sprintf("Mackerel resources exported from the organization %s", e.org),
+		"Resources":                e.resources,
+	}
+	if len(e.parameters) > 0 {
+		tmpl["Parameters"] = e.parameters
+	}
+	return tmpl, nil
+}
+
+func (e *exporter) find(ctx context.Context) (*exportedObjects, error) {
+	var objs exportedObjects
+	var err error
+	if objs.services, err = e.client.FindServices(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find services: %w", err)
+	}
+	if objs.monitors, err = e.client.FindMonitors(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find monitors: %w", err)
+	}
+	channels, err := e.client.FindNotificationChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification channels: %w", err)
+	}
+	for _, ch := range channels {
+		if _, ok := ch.(*mackerel.NotificationChannelBase); ok {
+			log.Printf("skip the notification channel %q: the type %s is not supported", ch.NotificationChannelName(), ch.NotificationChannelType())
+			continue
+		}
+		objs.channels = append(objs.channels, ch)
+	}
+	if objs.groups, err = e.client.FindNotificationGroups(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find notification groups: %w", err)
+	}
+	if objs.downtimes, err = e.client.FindDowntimes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find downtimes: %w", err)
+	}
+	dashboards, err := e.client.FindDashboards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dashboards: %w", err)
+	}
+	for _, d := range dashboards {
+		// the list of dashboards doesn't contain the widgets.
+		dashboard, err := e.client.FindDashboard(ctx, d.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the dashboard %s: %w", d.ID, err)
+		}
+		objs.dashboards = append(objs.dashboards, dashboard)
+	}
+	if objs.integrations, err = e.client.FindAWSIntegrations(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find aws integrations: %w", err)
+	}
+	return &objs, nil
+}
+
+func (e *exporter) physicalID(typ string, ids ...string) string {
+	return strings.Join(append([]string{"mkr", e.org, typ}, ids...), ":")
+}
+
+// assign assigns a new logical id to the object.
+func (e *exporter) assign(prefix, name, physicalID string) {
+	base := prefix + toLogicalName(name)
+	logicalID := base
+	for i := 2; e.used[logicalID]; i++ {
+		logicalID = fmt.Sprintf("%s%d", base, i)
+	}
+	e.used[logicalID] = true
+	e.logicalIDs[physicalID] = logicalID
+}
+
+// toLogicalName converts the name into alphanumeric CamelCase.
+// e.g. "my-service" to "MyService"
+func toLogicalName(name string) string {
+	var buf strings.Builder
+	upper := true
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// ref returns a reference to the generated resource.
+// If the resource is not generated, it returns the physical id as it is.
+func (e *exporter) ref(physicalID string) any {
+	if logicalID, ok := e.logicalIDs[physicalID]; ok {
+		return map[string]any{"Ref": logicalID}
+	}
+	return physicalID
+}
+
+func (e *exporter) serviceRef(name string) any {
+	return e.ref(e.physicalID("service", name))
+}
+
+func (e *exporter) roleRef(fullname string) any {
+	service, role, _ := strings.Cut(fullname, ":")
+	return e.ref(e.physicalID("role", service, role))
+}
+
+// scopeRef returns a reference to the service or the role.
+func (e *exporter) scopeRef(scope string) any {
+	if strings.Contains(scope, ":") {
+		return e.roleRef(scope)
+	}
+	return e.serviceRef(scope)
+}
+
+func (e *exporter) addResource(physicalID, typ string, properties map[string]any) {
+	e.resources[e.logicalIDs[physicalID]] = map[string]any{
+		"Type":       typ,
+		"Properties": properties,
+	}
+}
+
+func (e *exporter) exportService(s *mackerel.Service) {
+	serviceID := e.physicalID("service", s.Name)
+	properties := map[string]any{
+		"Name": s.Name,
+	}
+	if s.Memo != "" {
+		properties["Memo"] = s.Memo
+	}
+	e.addResource(serviceID, "Mackerel::Service", properties)
+	for _, r := range s.Roles {
+		e.addResource(e.physicalID("role", s.Name, r), "Mackerel::Role", map[string]any{
+			"Service": e.ref(serviceID),
+			"Name":    r,
+		})
+	}
+}
+
+func (e *exporter) exportMonitor(m mackerel.Monitor) error {
+	properties, err := e.convert(m)
+	if err != nil {
+		return fmt.Errorf("failed to convert the monitor %q: %w", m.MonitorName(), err)
+	}
+	properties["Adopt"] = true
+	e.addResource(e.physicalID("monitor", m.MonitorID()), "Mackerel::Monitor", properties)
+	return nil
+}
+
+func (e *exporter) exportNotificationChannel(ch mackerel.NotificationChannel) error {
+	properties, err := e.convert(ch)
+	if err != nil {
+		return fmt.Errorf("failed to convert the notification channel %q: %w", ch.NotificationChannelName(), err)
+	}
+	properties["Adopt"] = true
+	e.addResource(e.physicalID("notification-channel", ch.NotificationChannelID()), "Mackerel::NotificationChannel", properties)
+	return nil
+}
+
+func (e *exporter) exportNotificationGroup(g *mackerel.NotificationGroup) {
+	properties := map[string]any{
+		"Name":              g.Name,
+		"NotificationLevel": g.NotificationLevel.String(),
+		"Adopt":             true,
+	}
+	if len(g.ChildNotificationGroupIDs) > 0 {
+		ids := make([]any, 0, len(g.ChildNotificationGroupIDs))
+		for _, id := range g.ChildNotificationGroupIDs {
+			ids = append(ids, e.ref(e.physicalID("notification-group", id)))
+		}
+		properties["ChildNotificationGroupIds"] = ids
+	}
+	if len(g.ChildChannelIDs) > 0 {
+		ids := make([]any, 0, len(g.ChildChannelIDs))
+		for _, id := range g.ChildChannelIDs {
+			ids = append(ids, e.ref(e.physicalID("notification-channel", id)))
+		}
+		properties["ChildChannelIds"] = ids
+	}
+	if len(g.Services) > 0 {
+		services := make([]any, 0, len(g.Services))
+		for _, s := range g.Services {
+			services = append(services, map[string]any{
+				"Id": e.serviceRef(s.Name),
+			})
+		}
+		properties["Services"] = services
+	}
+	if len(g.Monitors) > 0 {
+		monitors := make([]any, 0, len(g.Monitors))
+		for _, m := range g.Monitors {
+			monitors = append(monitors, map[string]any{
+				"Id":          e.ref(e.physicalID("monitor", m.ID)),
+				"SkipDefault": m.SkipDefault,
+			})
+		}
+		properties["Monitors"] = monitors
+	}
+	e.addResource(e.physicalID("notification-group", g.ID), "Mackerel::NotificationGroup", properties)
+}
+
+func (e *exporter) exportDowntime(d *mackerel.Downtime) error {
+	properties, err := e.convert(d)
+	if err != nil {
+		return fmt.Errorf("failed to convert the downtime %q: %w", d.Name, err)
+	}
+	properties["Adopt"] = true
+	e.addResource(e.physicalID("downtime", d.ID), "Mackerel::Downtime", properties)
+	return nil
+}
+
+func (e *exporter) exportDashboard(d *mackerel.Dashboard) error {
+	properties, err := e.convert(d)
+	if err != nil {
+		return fmt.Errorf("failed to convert the dashboard %q: %w", d.Title, err)
+	}
+	if _, ok := properties["Widgets"]; !ok {
+		// Widgets is a required property.
+		properties["Widgets"] = []any{}
+	}
+	properties["Adopt"] = true
+	e.addResource(e.physicalID("dashboard", d.ID), "Mackerel::Dashboard", properties)
+	return nil
+}
+
+func (e *exporter) exportAWSIntegration(i *mackerel.AWSIntegration) {
+	physicalID := e.physicalID("aws-integration", i.ID)
+	properties := map[string]any{
+		"Name":   i.Name,
+		"Memo":   i.Memo,
+		"Region": i.Region,
+	}
+	if i.RoleArn != nil && *i.RoleArn != "" {
+		properties["RoleArn"] = *i.RoleArn
+	}
+	if i.ExternalID != nil && *i.ExternalID != "" {
+		// the external id is bound to the IAM role, so don't create a new one.
+		properties["ExternalId"] = e.physicalID("aws-integration-external-id", *i.ExternalID)
+	}
+	if i.Key != nil && *i.Key != "" {
+		// Mackerel doesn't return the secret key, so it is passed as a parameter.
+		name := e.logicalIDs[physicalID] + "SecretKey"
+		e.parameters[name] = map[string]any{
+			"Type":        "String",
+			"NoEcho":      true,
+			"Description": fmt.Sprintf("The secret access key of %s", *i.Key),
+		}
+		properties["Key"] = *i.Key
+		properties["SecretKey"] = map[string]any{"Ref": name}
+	}
+	if tags := parseTagList(i.IncludedTags); len(tags) > 0 {
+		properties["IncludedTags"] = tags
+	}
+	if tags := parseTagList(i.ExcludedTags); len(tags) > 0 {
+		properties["ExcludedTags"] = tags
+	}
+
+	names := make([]string, 0, len(i.Services))
+	for name := range i.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	services := make([]any, 0, len(names))
+	for _, name := range names {
+		s := i.Services[name]
+		service := map[string]any{
+			"ServiceId": name,
+			"Enable":    s.Enable,
+		}
+		if s.Role != nil && *s.Role != "" {
+			service["Role"] = e.roleRef(*s.Role)
+		}
+		if len(s.ExcludedMetrics) > 0 {
+			metrics := make([]any, 0, len(s.ExcludedMetrics))
+			for _, m := range s.ExcludedMetrics {
+				metrics = append(metrics, m)
+			}
+			service["ExcludedMetrics"] = metrics
+		}
+		if s.RetireAutomatically {
+			service["RetireAutomatically"] = true
+		}
+		services = append(services, service)
+	}
+	properties["Services"] = services
+
+	e.addResource(physicalID, "Mackerel::AWSIntegration", properties)
+}
+
+// parseTagList parses the list of tags, e.g. `key1:value1,"key:2":value2`.
+// It is the reverse of the conversion in cfn/aws_integration.go.
+// https://mackerel.io/docs/entry/integrations/aws#tag
+func parseTagList(s string) []any {
+	var tags []any
+	var fields []string
+	var buf strings.Builder
+	var quote rune
+	flushTag := func() {
+		fields = append(fields, buf.String())
+		buf.Reset()
+		if len(fields) == 1 {
+			fields = append(fields, "")
+		}
+		tags = append(tags, map[string]any{
+			"Key":   fields[0],
+			"Value": fields[1],
+		})
+		fields = fields[:0]
+	}
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				buf.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ':' && len(fields) == 0:
+			fields = append(fields, buf.String())
+			buf.Reset()
+		case r == ',':
+			flushTag()
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	if buf.Len() > 0 || len(fields) > 0 {
+		flushTag()
+	}
+	return tags
+}
+
+// convert converts the object of Mackerel API into the properties of the resource.
+// The names of JSON fields are converted into the names of the properties, e.g. notificationInterval to NotificationInterval,
+// and the values that refer to other objects are rewritten into the references.
+func (e *exporter) convert(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	ret, _ := e.convertValue("", obj).(map[string]any)
+	if ret == nil {
+		ret = map[string]any{}
+	}
+	return ret, nil
+}
+
+// the names of JSON fields that differ from the names of the properties.
+var propertyNames = map[string]string{
+	"userIds":      "Users",
+	"hostId":       "Host",
+	"roleFullname": "Role",
+	"serviceName":  "Service",
+}
+
+// the JSON fields that are not the properties.
+var ignoredFields = map[string]bool{
+	"id":        true,
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+func (e *exporter) convertValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, value := range v {
+			if ignoredFields[k] {
+				continue
+			}
+			name, ok := propertyNames[k]
+			if !ok {
+				name = strings.ToUpper(k[:1]) + k[1:]
+			}
+			if converted := e.convertValue(k, value); !isEmpty(converted) {
+				ret[name] = converted
+			}
+		}
+		return ret
+	case []any:
+		ret := make([]any, 0, len(v))
+		for _, item := range v {
+			ret = append(ret, e.convertValue(key, item))
+		}
+		return ret
+	case string:
+		switch key {
+		case "scopes", "excludeScopes":
+			return e.scopeRef(v)
+		case "service", "serviceName", "serviceScopes", "serviceExcludeScopes":
+			return e.serviceRef(v)
+		case "roleFullname", "roleScopes", "roleExcludeScopes":
+			return e.roleRef(v)
+		case "monitorScopes", "monitorExcludeScopes":
+			return e.ref(e.physicalID("monitor", v))
+		case "hostId":
+			return e.physicalID("host", v)
+		case "userIds":
+			return e.physicalID("user", e.emails[v])
+		}
+		return v
+	}
+	return v
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
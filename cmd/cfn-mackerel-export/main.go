@@ -0,0 +1,85 @@
+// Command cfn-mackerel-export exports the objects in an existing Mackerel organization into a template.
+//
+// The services, the roles, the monitors, the notification channels and groups,
+// the downtimes, the dashboards and the AWS integrations are exported as the Mackerel:: resources,
+// and the references between them are rewritten into !Ref.
+// The monitors, the notification channels and groups, the downtimes and the dashboards have Adopt: true,
+// so that deploying the template takes over the existing objects instead of creating new ones.
+//
+// Usage:
+//
+//	MACKEREL_APIKEY=... cfn-mackerel-export -o template.yaml
+//
+// The template is validated against the resource specification before it is written.
+// Use -transform to change the name of the macro if MacroName of the macro stack is not "Mackerel".
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey/aws"
+	"github.com/shogo82148/cfn-mackerel-macro/spec"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cfn-mackerel-export: ")
+
+	var output, specFile, transform string
+	flag.StringVar(&output, "o", "", "the output file; the standard output is used if empty")
+	flag.StringVar(&specFile, "spec", "cfn-resource-specification.json", "the resource specification of the Mackerel:: resources")
+	flag.StringVar(&transform, "transform", "Mackerel", "the name of the macro, which is MacroName of the macro stack")
+	flag.Parse()
+
+	if err := run(context.Background(), output, specFile, transform); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, output, specFile, transform string) error {
+	s, err := spec.Load(specFile)
+	if err != nil {
+		return err
+	}
+
+	provider, err := aws.LoadDefaultProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load aws config: %w", err)
+	}
+	client := &mackerel.Client{
+		APIKeyProvider: provider,
+	}
+	if base := os.Getenv("MACKEREL_APIURL"); base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return fmt.Errorf("failed to parse base url: %w", err)
+		}
+		client.BaseURL = u
+	}
+
+	e := newExporter(client)
+	e.transform = transform
+	tmpl, err := e.export(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateTemplate(tmpl); err != nil {
+		return fmt.Errorf("the exported template is invalid: %w", err)
+	}
+
+	data, err := marshalYAML(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to encode the template: %w", err)
+	}
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// marshalYAML encodes the template in YAML format.
+// It supports only the values decoded from JSON, which is enough for the templates.
+// {"Ref": "Foo"} is written in the short form, i.e. !Ref Foo.
+func marshalYAML(v map[string]any) ([]byte, error) {
+	var e yamlEncoder
+	if err := e.writeMapping(v, 0, false); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+type yamlEncoder struct {
+	buf bytes.Buffer
+}
+
+// the keys that are written first, for readability.
+var keyOrder = map[string]int{
+	"AWSTemplateFormatVersion": 1,
+	"Transform":                2,
+	"Description":              3,
+	"Parameters":               4,
+	"Resources":                5,
+	"Outputs":                  6,
+	"Type":                     7,
+	"Properties":               8,
+}
+
+func orderedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		oi, oj := keyOrder[keys[i]], keyOrder[keys[j]]
+		if oi == 0 {
+			oi = math.MaxInt
+		}
+		if oj == 0 {
+			oj = math.MaxInt
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// writeMapping writes the mapping.
+// If inline is true, the first key is written without indentation, e.g. after "- ".
+func (e *yamlEncoder) writeMapping(m map[string]any, indent int, inline bool) error {
+	for i, key := range orderedKeys(m) {
+		if i > 0 || !inline {
+			e.writeIndent(indent)
+		}
+		e.buf.WriteString(quoteYAML(key))
+		e.buf.WriteByte(':')
+		if err := e.writeValue(m[key], indent); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (e *yamlEncoder) writeSequence(list []any, indent int) error {
+	for i, item := range list {
+		e.writeIndent(indent)
+		e.buf.WriteByte('-')
+		var err error
+		if m, ok := item.(map[string]any); ok && !isInlineYAML(m) {
+			e.buf.WriteByte(' ')
+			err = e.writeMapping(m, indent+2, true)
+		} else {
+			err = e.writeValue(item, indent)
+		}
+		if err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// writeValue writes the value after "key:" or "-".
+func (e *yamlEncoder) writeValue(v any, indent int) error {
+	if s, ok := inlineYAML(v); ok {
+		e.buf.WriteByte(' ')
+		e.buf.WriteString(s)
+		e.buf.WriteByte('\n')
+		return nil
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		e.buf.WriteByte('\n')
+		return e.writeMapping(v, indent+2, false)
+	case []any:
+		e.buf.WriteByte('\n')
+		return e.writeSequence(v, indent+2)
+	case string:
+		e.writeLiteral(v, indent+2)
+		return nil
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+// writeLiteral writes the multi-line string in the literal style.
+func (e *yamlEncoder) writeLiteral(s string, indent int) {
+	body := strings.TrimRight(s, "\n")
+	switch len(s) - len(body) {
+	case 0:
+		e.buf.WriteString(" |-\n")
+	case 1:
+		e.buf.WriteString(" |\n")
+	default:
+		e.buf.WriteString(" |+\n")
+		body = s[:len(s)-1]
+	}
+	for line := range strings.SplitSeq(body, "\n") {
+		if line != "" {
+			e.writeIndent(indent)
+			e.buf.WriteString(line)
+		}
+		e.buf.WriteByte('\n')
+	}
+}
+
+func (e *yamlEncoder) writeIndent(indent int) {
+	for range indent {
+		e.buf.WriteByte(' ')
+	}
+}
+
+// inlineYAML returns the value in the flow style if possible.
+func inlineYAML(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "null", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10), true
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case string:
+		if useLiteral(v) {
+			return "", false
+		}
+		return quoteYAML(v), true
+	case []any:
+		if len(v) == 0 {
+			return "[]", true
+		}
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", true
+		}
+		if len(v) != 1 {
+			return "", false
+		}
+		if ref, ok := v["Ref"].(string); ok {
+			return "!Ref " + quoteYAML(ref), true
+		}
+	}
+	return "", false
+}
+
+// isInlineYAML reports whether v is written in the flow style.
+func isInlineYAML(v any) bool {
+	_, ok := inlineYAML(v)
+	return ok
+}
+
+// useLiteral reports whether s should be written in the literal style.
+func useLiteral(s string) bool {
+	if !strings.Contains(s, "\n") {
+		return false
+	}
+	// the literal style requires an indentation indicator if the first line starts with a space.
+	if strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\n") {
+		return false
+	}
+	for _, r := range s {
+		if r != '\n' && r != '\t' && (r < 0x20 || r == 0x7f) {
+			return false
+		}
+	}
+	return true
+}
+
+var plainPattern = regexp.MustCompile(`^[A-Za-z_][-A-Za-z0-9_./@:]*(?: [-A-Za-z0-9_./@:]+)*$`)
+
+// the plain scalars that YAML parsers may interpret as non-string values.
+var ambiguousWords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true,
+}
+
+// quoteYAML returns s as a plain scalar if it is safe, otherwise as a double-quoted scalar.
+func quoteYAML(s string) string {
+	// ": " and the trailing ":" are the indicators of mappings.
+	if plainPattern.MatchString(s) && !strings.Contains(s, ": ") && !strings.HasSuffix(s, ":") && !ambiguousWords[strings.ToLower(s)] {
+		return s
+	}
+	// the escape sequences of Go, e.g. \n, \x7f and \u2028, are also valid in double-quoted scalars of YAML.
+	return strconv.Quote(s)
+}
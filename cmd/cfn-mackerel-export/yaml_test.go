@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	got, err := marshalYAML(map[string]any{
+		"Resources": map[string]any{
+			"Monitor": map[string]any{
+				"Properties": map[string]any{
+					"Name":     "cpu: high",
+					"Memo":     "line1\nline2\n",
+					"IsMute":   "true",
+					"Warning":  float64(80),
+					"Critical": 90.5,
+					"Scopes": []any{
+						map[string]any{"Ref": "Service"},
+						map[string]any{"Ref": "Role"},
+					},
+					"Headers": []any{
+						map[string]any{"Name": "Cache-Control", "Value": "no-cache"},
+					},
+					"ExcludeScopes": []any{},
+				},
+				"Type": "Mackerel::Monitor",
+			},
+		},
+		"Transform": "Mackerel",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Transform: Mackerel
+Resources:
+  Monitor:
+    Type: Mackerel::Monitor
+    Properties:
+      Critical: 90.5
+      ExcludeScopes: []
+      Headers:
+        - Name: Cache-Control
+          Value: no-cache
+      IsMute: "true"
+      Memo: |
+        line1
+        line2
+      Name: "cpu: high"
+      Scopes:
+        - !Ref Service
+        - !Ref Role
+      Warning: 80
+`
+	if diff := cmp.Diff(string(got), want); diff != "" {
+		t.Errorf("yaml differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestMarshalYAML_unsupportedType(t *testing.T) {
+	_, err := marshalYAML(map[string]any{
+		"Resources": map[string]any{
+			"Monitor": map[string]any{
+				"Properties": map[string]any{
+					"Scopes": []any{struct{}{}},
+				},
+			},
+		},
+	})
+	if err == nil || err.Error() != "Resources: Monitor: Properties: Scopes: [0]: unsupported type: struct {}" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQuoteYAML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"foo", "foo"},
+		{"foo bar", "foo bar"},
+		{"Mackerel::Service", "Mackerel::Service"},
+		{"", `""`},
+		{"Y", `"Y"`},
+		{"null", `"null"`},
+		{"2010-09-09", `"2010-09-09"`},
+		{"key: value", `"key: value"`},
+		{"key:", `"key:"`},
+		{"- item", `"- item"`},
+		{"<b>", `"<b>"`},
+		{`"quoted"`, `"\"quoted\""`},
+		{"tab\there", `"tab\there"`},
+	}
+	for _, tt := range tests {
+		if got := quoteYAML(tt.in); got != tt.want {
+			t.Errorf("quoteYAML(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTagList(t *testing.T) {
+	got := parseTagList(`service:web,"a:b":'c,d',noValue`)
+	want := []any{
+		map[string]any{"Key": "service", "Value": "web"},
+		map[string]any{"Key": "a:b", "Value": "c,d"},
+		map[string]any{"Key": "noValue", "Value": ""},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("tags differ: (-got +want)\n%s", diff)
+	}
+	if got := parseTagList(""); len(got) != 0 {
+		t.Errorf("want empty, got %v", got)
+	}
+}
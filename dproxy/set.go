@@ -3,9 +3,10 @@ package dproxy
 import "strconv"
 
 type setProxy struct {
-	values []any
-	parent frame
-	label  string
+	values  []any
+	parent  frame
+	label   string
+	tracker *Tracker
 }
 
 // setProxy implements ProxySet
@@ -117,9 +118,10 @@ func (p *setProxy) ProxyArray() ([]Proxy, error) {
 	r := make([]Proxy, 0, len(p.values))
 	for i, v := range p.values {
 		r = append(r, &valueProxy{
-			value:  v,
-			parent: p,
-			label:  "[" + strconv.Itoa(i) + "]",
+			value:   v,
+			parent:  p,
+			label:   "[" + strconv.Itoa(i) + "]",
+			tracker: p.tracker,
 		})
 	}
 	return r, nil
@@ -131,13 +133,15 @@ func (p *setProxy) A(n int) Proxy {
 		return notfoundError(p, a)
 	}
 	return &valueProxy{
-		value:  p.values[n],
-		parent: p,
-		label:  a,
+		value:   p.values[n],
+		parent:  p,
+		label:   a,
+		tracker: p.tracker,
 	}
 }
 
 func (p *setProxy) Q(k string) ProxySet {
+	p.tracker.consume(p)
 	w := findAll(p.values, k)
 	return &setProxy{
 		values: w,
@@ -147,6 +151,7 @@ func (p *setProxy) Q(k string) ProxySet {
 }
 
 func (p *setProxy) Qc(k string) ProxySet {
+	p.tracker.consume(p)
 	r := make([]any, 0, len(p.values))
 	for _, v := range p.values {
 		switch v := v.(type) {
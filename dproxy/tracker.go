@@ -0,0 +1,124 @@
+package dproxy
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Tracker records the keys of maps accessed through proxies,
+// to find the keys that nobody cares, e.g. typos of property names.
+type Tracker struct {
+	root any
+
+	// accessed is the keys accessed by M, grouped by the address of the map.
+	accessed map[string]map[string]bool
+
+	// consumed is the addresses of the values that are read as a whole by Value.
+	consumed map[string]bool
+}
+
+// UnvisitedKey is a key of a map that is not accessed through the proxy.
+type UnvisitedKey struct {
+	// Address is the full address of the key, e.g. "data.custom[0].kustom".
+	Address string
+
+	// Key is the unvisited key.
+	Key string
+
+	// Accessed is the keys of the same map that are accessed, in the sorted order.
+	// It includes the keys that are not found in the map.
+	Accessed []string
+}
+
+// Track creates a new Proxy instance for v, and a Tracker that records the keys accessed through it.
+// The tracking propagates to the proxies derived by M, A, ProxySet, etc.
+// The values returned by Value are treated as accessed entirely.
+func Track(v any) (Proxy, *Tracker) {
+	t := &Tracker{
+		root:     v,
+		accessed: make(map[string]map[string]bool),
+		consumed: make(map[string]bool),
+	}
+	return &valueProxy{value: v, tracker: t}, t
+}
+
+func (t *Tracker) access(f frame, key string) {
+	if t == nil {
+		return
+	}
+	addr := address(f)
+	keys, ok := t.accessed[addr]
+	if !ok {
+		keys = make(map[string]bool)
+		t.accessed[addr] = keys
+	}
+	keys[key] = true
+}
+
+func (t *Tracker) consume(f frame) {
+	if t == nil {
+		return
+	}
+	t.consumed[address(f)] = true
+}
+
+// Unvisited returns the keys of maps that are not accessed, in the order of their addresses.
+// The children of the unvisited keys are not reported.
+func (t *Tracker) Unvisited() []UnvisitedKey {
+	var ret []UnvisitedKey
+	t.walk("", t.root, &ret)
+	return ret
+}
+
+func (t *Tracker) walk(addr string, v any, ret *[]UnvisitedKey) {
+	if t.consumed[addr] {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		accessed := t.accessed[addr]
+		for _, k := range keys {
+			child := k
+			if addr != "" {
+				child = addr + "." + k
+			}
+			if !accessed[k] {
+				*ret = append(*ret, UnvisitedKey{
+					Address:  child,
+					Key:      k,
+					Accessed: sortedKeys(accessed),
+				})
+				continue
+			}
+			t.walk(child, v[k], ret)
+		}
+	case []any:
+		for i, item := range v {
+			t.walk(addr+"["+strconv.Itoa(i)+"]", item, ret)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// address returns the full address of the frame, or an empty string for the root.
+func address(f frame) string {
+	for g := f; g != nil; g = g.parentFrame() {
+		if g.frameLabel() != "" {
+			return fullAddress(f)
+		}
+	}
+	return ""
+}
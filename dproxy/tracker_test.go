@@ -0,0 +1,50 @@
+package dproxy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTracker(t *testing.T) {
+	v := parseJSON(`{
+		"name": "foo",
+		"nmae": "typo",
+		"widgets": [
+			{"type": "graph", "graph": {"type": "host", "hots": "typo"}},
+			{"type": "markdown", "markdown": "# hello", "extra": true}
+		],
+		"document": {"anything": "goes"},
+		"unused": {"nested": "value"}
+	}`)
+
+	p, tracker := Track(v)
+	var d Drain
+	d.String(p.M("name"))
+	d.String(Default(p.M("memo"), ""))
+	for _, w := range d.ProxyArray(p.M("widgets").ProxySet()) {
+		switch d.String(w.M("type")) {
+		case "graph":
+			d.String(w.M("graph").M("type"))
+			d.String(Default(w.M("graph").M("host"), ""))
+		case "markdown":
+			d.String(w.M("markdown"))
+		}
+	}
+	if _, err := p.M("document").Value(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CombineErrors(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []UnvisitedKey{
+		{Address: "nmae", Key: "nmae", Accessed: []string{"document", "memo", "name", "widgets"}},
+		{Address: "unused", Key: "unused", Accessed: []string{"document", "memo", "name", "widgets"}},
+		{Address: "widgets[0].graph.hots", Key: "hots", Accessed: []string{"host", "type"}},
+		{Address: "widgets[1].extra", Key: "extra", Accessed: []string{"markdown", "type"}},
+	}
+	if diff := cmp.Diff(tracker.Unvisited(), want); diff != "" {
+		t.Errorf("unvisited keys differ: (-got +want)\n%s", diff)
+	}
+}
@@ -6,9 +6,10 @@ import (
 )
 
 type valueProxy struct {
-	value  any
-	parent frame
-	label  string
+	value   any
+	parent  frame
+	label   string
+	tracker *Tracker
 }
 
 // valueProxy implements Proxy.
@@ -19,6 +20,7 @@ func (p *valueProxy) Nil() bool {
 }
 
 func (p *valueProxy) Value() (any, error) {
+	p.tracker.consume(p)
 	return p.value, nil
 }
 
@@ -395,9 +397,10 @@ func (p *valueProxy) A(n int) Proxy {
 			return notfoundError(p, a)
 		}
 		return &valueProxy{
-			value:  v[n],
-			parent: p,
-			label:  a,
+			value:   v[n],
+			parent:  p,
+			label:   a,
+			tracker: p.tracker,
 		}
 	default:
 		return typeError(p, Tarray, v)
@@ -407,15 +410,17 @@ func (p *valueProxy) A(n int) Proxy {
 func (p *valueProxy) M(k string) Proxy {
 	switch v := p.value.(type) {
 	case map[string]any:
+		p.tracker.access(p, k)
 		a := "." + k
 		w, ok := v[k]
 		if !ok {
 			return notfoundError(p, a)
 		}
 		return &valueProxy{
-			value:  w,
-			parent: p,
-			label:  a,
+			value:   w,
+			parent:  p,
+			label:   a,
+			tracker: p.tracker,
 		}
 	default:
 		return typeError(p, Tmap, v)
@@ -430,8 +435,9 @@ func (p *valueProxy) ProxySet() ProxySet {
 	switch v := p.value.(type) {
 	case []any:
 		return &setProxy{
-			values: v,
-			parent: p,
+			values:  v,
+			parent:  p,
+			tracker: p.tracker,
 		}
 	default:
 		return typeError(p, Tarray, v)
@@ -439,6 +445,7 @@ func (p *valueProxy) ProxySet() ProxySet {
 }
 
 func (p *valueProxy) Q(k string) ProxySet {
+	p.tracker.consume(p)
 	w := findAll(p.value, k)
 	return &setProxy{
 		values: w,
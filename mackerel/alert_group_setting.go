@@ -0,0 +1,75 @@
+package mackerel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AlertGroupSetting is a setting of alert groups.
+type AlertGroupSetting struct {
+	ID                   string   `json:"id,omitempty"`
+	Name                 string   `json:"name"`
+	Memo                 string   `json:"memo,omitempty"`
+	ServiceScopes        []string `json:"serviceScopes,omitempty"`
+	RoleScopes           []string `json:"roleScopes,omitempty"`
+	MonitorScopes        []string `json:"monitorScopes,omitempty"`
+	NotificationInterval uint64   `json:"notificationInterval,omitempty"`
+}
+
+// FindAlertGroupSettings finds alert group settings.
+// https://mackerel.io/api-docs/entry/alert-group-settings#list
+func (c *Client) FindAlertGroupSettings(ctx context.Context) ([]*AlertGroupSetting, error) {
+	var ret struct {
+		AlertGroupSettings []*AlertGroupSetting `json:"alertGroupSettings"`
+	}
+	_, err := c.do(ctx, http.MethodGet, "/api/v0/alert-group-settings", nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret.AlertGroupSettings, nil
+}
+
+// FindAlertGroupSetting finds an alert group setting.
+// https://mackerel.io/api-docs/entry/alert-group-settings#get
+func (c *Client) FindAlertGroupSetting(ctx context.Context, settingID string) (*AlertGroupSetting, error) {
+	var ret AlertGroupSetting
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v0/alert-group-settings/%s", settingID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateAlertGroupSetting creates a new alert group setting.
+// https://mackerel.io/api-docs/entry/alert-group-settings#create
+func (c *Client) CreateAlertGroupSetting(ctx context.Context, param *AlertGroupSetting) (*AlertGroupSetting, error) {
+	var ret AlertGroupSetting
+	_, err := c.do(ctx, http.MethodPost, "/api/v0/alert-group-settings", param, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateAlertGroupSetting updates an alert group setting.
+// https://mackerel.io/api-docs/entry/alert-group-settings#update
+func (c *Client) UpdateAlertGroupSetting(ctx context.Context, settingID string, param *AlertGroupSetting) (*AlertGroupSetting, error) {
+	var ret AlertGroupSetting
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v0/alert-group-settings/%s", settingID), param, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteAlertGroupSetting deletes an alert group setting.
+// https://mackerel.io/api-docs/entry/alert-group-settings#delete
+func (c *Client) DeleteAlertGroupSetting(ctx context.Context, settingID string) (*AlertGroupSetting, error) {
+	var ret AlertGroupSetting
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v0/alert-group-settings/%s", settingID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
@@ -0,0 +1,300 @@
+package mackerel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindAlertGroupSettings(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/alert-group-settings" {
+			t.Errorf("unexpected request path: want %s, got %s", "/api/v0/alert-group-settings", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request method: want %s, got %s", "GET", r.Method)
+		}
+		ret := map[string]any{
+			"alertGroupSettings": []any{
+				map[string]any{
+					"id":   "abcde0",
+					"name": "Alert Group #0",
+				},
+				map[string]any{
+					"id":                   "abcde1",
+					"name":                 "Alert Group #1",
+					"memo":                 "Memo #1",
+					"serviceScopes":        []string{"service1"},
+					"roleScopes":           []string{"service2: role1"},
+					"monitorScopes":        []string{"monitor0"},
+					"notificationInterval": 60,
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ret); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.FindAlertGroupSettings(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := []*AlertGroupSetting{
+		{
+			ID:   "abcde0",
+			Name: "Alert Group #0",
+		},
+		{
+			ID:                   "abcde1",
+			Name:                 "Alert Group #1",
+			Memo:                 "Memo #1",
+			ServiceScopes:        []string{"service1"},
+			RoleScopes:           []string{"service2: role1"},
+			MonitorScopes:        []string{"monitor0"},
+			NotificationInterval: 60,
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("alert group settings differ: (-got +want)\n%s", diff)
+	}
+}
+
+func TestFindAlertGroupSetting(t *testing.T) {
+	const (
+		settingID = "abcde1"
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/alert-group-settings/"+settingID {
+			t.Errorf("unexpected request path: want %s, got %s", "/api/v0/alert-group-settings/"+settingID, r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request method: want %s, got %s", "GET", r.Method)
+		}
+		ret := map[string]any{
+			"id":            settingID,
+			"name":          "Alert Group #1",
+			"serviceScopes": []string{"service1"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ret); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.FindAlertGroupSetting(context.Background(), settingID)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := &AlertGroupSetting{
+		ID:            settingID,
+		Name:          "Alert Group #1",
+		ServiceScopes: []string{"service1"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestCreateAlertGroupSetting(t *testing.T) {
+	const (
+		settingID = "9rxGOHfVF8F"
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/alert-group-settings" {
+			t.Errorf("unexpected request path: want %s, got %s", "/api/v0/alert-group-settings", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected request method: want %s, got %s", "POST", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		var data any
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		want := map[string]any{
+			"name":                 "alert group",
+			"memo":                 "memo",
+			"serviceScopes":        []any{"service1"},
+			"roleScopes":           []any{"service2:role1"},
+			"monitorScopes":        []any{"monitor0"},
+			"notificationInterval": 60.0,
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+		}
+		w.WriteHeader(http.StatusOK)
+		want["id"] = settingID
+		if err := json.NewEncoder(w).Encode(want); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	param := &AlertGroupSetting{
+		Name:                 "alert group",
+		Memo:                 "memo",
+		ServiceScopes:        []string{"service1"},
+		RoleScopes:           []string{"service2:role1"},
+		MonitorScopes:        []string{"monitor0"},
+		NotificationInterval: 60,
+	}
+	got, err := c.CreateAlertGroupSetting(context.Background(), param)
+	if err != nil {
+		t.Error(err)
+	}
+
+	param.ID = settingID
+	if diff := cmp.Diff(got, param); diff != "" {
+		t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestUpdateAlertGroupSetting(t *testing.T) {
+	const (
+		settingID = "9rxGOHfVF8F"
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/alert-group-settings/"+settingID {
+			t.Errorf("unexpected request path: want %s, got %s", "/api/v0/alert-group-settings/"+settingID, r.URL.Path)
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected request method: want %s, got %s", "PUT", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		var data any
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		want := map[string]any{
+			"name":          "alert group",
+			"serviceScopes": []any{"service1"},
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+		}
+		w.WriteHeader(http.StatusOK)
+		want["id"] = settingID
+		if err := json.NewEncoder(w).Encode(want); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	param := &AlertGroupSetting{
+		Name:          "alert group",
+		ServiceScopes: []string{"service1"},
+	}
+	got, err := c.UpdateAlertGroupSetting(context.Background(), settingID, param)
+	if err != nil {
+		t.Error(err)
+	}
+
+	param.ID = settingID
+	if diff := cmp.Diff(got, param); diff != "" {
+		t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDeleteAlertGroupSetting(t *testing.T) {
+	const (
+		settingID = "9rxGOHfVF8F"
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/alert-group-settings/"+settingID {
+			t.Errorf("unexpected request path: want %s, got %s", "/api/v0/alert-group-settings/"+settingID, r.URL.Path)
+		}
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected request method: want %s, got %s", "DELETE", r.Method)
+		}
+		ret := map[string]any{
+			"id":   settingID,
+			"name": "alert group",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ret); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.DeleteAlertGroupSetting(context.Background(), settingID)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := &AlertGroupSetting{
+		ID:   settingID,
+		Name: "alert group",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("alert group setting differs: (-got +want)\n%s", diff)
+	}
+}
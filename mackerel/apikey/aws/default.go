@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
 	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey"
@@ -16,6 +17,16 @@ func LoadDefaultProvider(ctx context.Context) (mackerel.APIKeyProvider, error) {
 		return nil, err
 	}
 
+	// Secrets Manager is not a member of the chain,
+	// because the client caches the api keys from the chain forever, and it breaks rotation.
+	if _, ok := os.LookupEnv("MACKEREL_APIKEY_SECRET_ID"); ok {
+		return &SecretsManager{
+			APIKeyProvider: apikey.NewEnvironment("MACKEREL_APIKEY_SECRET_ID"),
+			Config:         cfg,
+			Key:            os.Getenv("MACKEREL_APIKEY_SECRET_KEY"),
+		}, nil
+	}
+
 	var decrypt bool
 	if os.Getenv("MACKEREL_APIKEY_WITH_DECRYPT") != "" {
 		decrypt = true
@@ -39,3 +50,39 @@ func LoadDefaultProvider(ctx context.Context) (mackerel.APIKeyProvider, error) {
 	)
 	return provider, nil
 }
+
+// Resolver creates the providers for the api keys that resources specify.
+type Resolver struct {
+	Config aws.Config
+}
+
+// LoadDefaultResolver returns default resolver.
+func LoadDefaultResolver(ctx context.Context) (*Resolver, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{
+		Config: cfg,
+	}, nil
+}
+
+// ParameterProvider returns a provider which gets the api key from the SSM parameter.
+// The parameter is decrypted if it is a SecureString.
+func (r *Resolver) ParameterProvider(name string) mackerel.APIKeyProvider {
+	return &SSM{
+		APIKeyProvider: apikey.NewStatic(name),
+		Config:         r.Config,
+		WithDecryption: true,
+	}
+}
+
+// SecretProvider returns a provider which gets the api key from the secret of Secrets Manager.
+// The key of the api key in JSON secrets is configured by MACKEREL_APIKEY_SECRET_KEY environment value.
+func (r *Resolver) SecretProvider(secretID string) mackerel.APIKeyProvider {
+	return &SecretsManager{
+		APIKeyProvider: apikey.NewStatic(secretID),
+		Config:         r.Config,
+		Key:            os.Getenv("MACKEREL_APIKEY_SECRET_KEY"),
+	}
+}
@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// DefaultSecretRefreshInterval is the default interval for refreshing the api key from AWS Secrets Manager.
+const DefaultSecretRefreshInterval = 5 * time.Minute
+
+// SecretsManager gets the api key from AWS Secrets Manager.
+// The api key is cached for RefreshInterval, so the rotated api key is used after the interval.
+type SecretsManager struct {
+	// APIKeyProvider provides the id of the secret.
+	mackerel.APIKeyProvider
+	Config aws.Config
+
+	// Key is the key of the api key in the JSON secret, e.g. {"apikey": "..."}.
+	// If it is empty, the whole secret string is used as the api key.
+	Key string
+
+	// RefreshInterval is the interval for refreshing the cached api key.
+	// If it is zero, DefaultSecretRefreshInterval is used.
+	RefreshInterval time.Duration
+
+	mu        sync.Mutex
+	apikey    string
+	expiresAt time.Time
+}
+
+// MackerelAPIKey implements mackerel.APIKeyProvider
+func (p *SecretsManager) MackerelAPIKey(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.apikey != "" && now.Before(p.expiresAt) {
+		return p.apikey, nil
+	}
+
+	secretID, err := p.APIKeyProvider.MackerelAPIKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	svc := secretsmanager.NewFromConfig(p.Config)
+	resp, err := svc.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.SecretString == nil {
+		return "", fmt.Errorf("the secret %s doesn't have a secret string", secretID)
+	}
+	apikey, err := parseSecret(aws.ToString(resp.SecretString), p.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the secret %s: %w", secretID, err)
+	}
+
+	interval := p.RefreshInterval
+	if interval == 0 {
+		interval = DefaultSecretRefreshInterval
+	}
+	p.apikey = apikey
+	p.expiresAt = now.Add(interval)
+	return apikey, nil
+}
+
+// RefreshesMackerelAPIKey implements mackerel.APIKeyRefresher.
+// SecretsManager caches the api key by itself, and refreshes it for rotation.
+func (p *SecretsManager) RefreshesMackerelAPIKey() bool {
+	return true
+}
+
+// InvalidateMackerelAPIKey implements mackerel.APIKeyInvalidator.
+// The api key is fetched from Secrets Manager again on the next call.
+func (p *SecretsManager) InvalidateMackerelAPIKey() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.apikey = ""
+	p.expiresAt = time.Time{}
+}
+
+func parseSecret(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var v map[string]any
+	if err := json.Unmarshal([]byte(secret), &v); err != nil {
+		return "", errors.New("the secret is not a JSON object")
+	}
+	apikey, ok := v[key].(string)
+	if !ok {
+		return "", fmt.Errorf("key %q is not found in the secret", key)
+	}
+	return apikey, nil
+}
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey"
+)
+
+func TestSecretsManager(t *testing.T) {
+	secret := `{"apikey":"old-api-key"}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Amz-Target"); got != "secretsmanager.GetSecretValue" {
+			t.Errorf("unexpected target: %s", got)
+		}
+		var in struct {
+			SecretId string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Error(err)
+		}
+		if in.SecretId != "my-secret" {
+			t.Errorf("unexpected secret id: want %s, got %s", "my-secret", in.SecretId)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		json.NewEncoder(w).Encode(map[string]any{
+			"Name":         "my-secret",
+			"SecretString": secret,
+		})
+	}))
+	defer ts.Close()
+
+	p := &SecretsManager{
+		APIKeyProvider: apikey.NewStatic("my-secret"),
+		Config: aws.Config{
+			Region:       "ap-northeast-1",
+			Credentials:  aws.AnonymousCredentials{},
+			BaseEndpoint: aws.String(ts.URL),
+		},
+		Key: "apikey",
+	}
+	got, err := p.MackerelAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "old-api-key" {
+		t.Errorf("unexpected api key: want %s, got %s", "old-api-key", got)
+	}
+
+	// the api key is cached until it expires.
+	secret = `{"apikey":"new-api-key"}`
+	got, err = p.MackerelAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "old-api-key" {
+		t.Errorf("unexpected api key: want %s, got %s", "old-api-key", got)
+	}
+
+	// the rotated api key is used after the cache expires.
+	p.expiresAt = time.Now().Add(-time.Second)
+	got, err = p.MackerelAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "new-api-key" {
+		t.Errorf("unexpected api key: want %s, got %s", "new-api-key", got)
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	tests := []struct {
+		secret  string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{secret: "plain-api-key", key: "", want: "plain-api-key"},
+		{secret: `{"apikey":"json-api-key"}`, key: "apikey", want: "json-api-key"},
+		{secret: `{"apikey":"json-api-key"}`, key: "unknown", wantErr: true},
+		{secret: "plain-api-key", key: "apikey", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSecret(tt.secret, tt.key)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSecret(%q, %q): want error, got nil", tt.secret, tt.key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSecret(%q, %q): unexpected error: %v", tt.secret, tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSecret(%q, %q): want %q, got %q", tt.secret, tt.key, tt.want, got)
+		}
+	}
+}
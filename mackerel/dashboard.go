@@ -681,12 +681,14 @@ func (r *GraphRangeAbsolute) UnmarshalJSON(b []byte) error {
 // FindDashboards finds dashboards.
 // https://mackerel.io/api-docs/entry/dashboards#list
 func (c *Client) FindDashboards(ctx context.Context) ([]*Dashboard, error) {
-	ret := []*Dashboard{}
+	var ret struct {
+		Dashboards []*Dashboard `json:"dashboards"`
+	}
 	_, err := c.do(ctx, http.MethodGet, "/api/v0/dashboards", nil, &ret)
 	if err != nil {
 		return nil, err
 	}
-	return ret, nil
+	return ret.Dashboards, nil
 }
 
 // FindDashboard find the dashboard.
@@ -556,7 +556,7 @@ func TestFindDashboard(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 				enc := json.NewEncoder(w)
-				if err := enc.Encode([]any{tc.resp}); err != nil {
+				if err := enc.Encode(map[string]any{"dashboards": []any{tc.resp}}); err != nil {
 					t.Error(err)
 				}
 			}))
@@ -0,0 +1,70 @@
+package mackerel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GraphAnnotation is an annotation on graphs.
+type GraphAnnotation struct {
+	ID          string    `json:"id,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	From        Timestamp `json:"from"`
+	To          Timestamp `json:"to"`
+	Service     string    `json:"service"`
+	Roles       []string  `json:"roles,omitempty"`
+}
+
+// FindGraphAnnotations finds the graph annotations of the service in the period.
+// https://mackerel.io/api-docs/entry/graph-annotation#get
+func (c *Client) FindGraphAnnotations(ctx context.Context, service string, from, to int64) ([]*GraphAnnotation, error) {
+	q := url.Values{}
+	q.Set("service", service)
+	q.Set("from", strconv.FormatInt(from, 10))
+	q.Set("to", strconv.FormatInt(to, 10))
+	var data struct {
+		GraphAnnotations []*GraphAnnotation `json:"graphAnnotations"`
+	}
+	_, err := c.do(ctx, http.MethodGet, "/api/v0/graph-annotations?"+q.Encode(), nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data.GraphAnnotations, nil
+}
+
+// CreateGraphAnnotation creates a new graph annotation.
+// https://mackerel.io/api-docs/entry/graph-annotation#create
+func (c *Client) CreateGraphAnnotation(ctx context.Context, param *GraphAnnotation) (*GraphAnnotation, error) {
+	var ret GraphAnnotation
+	_, err := c.do(ctx, http.MethodPost, "/api/v0/graph-annotations", param, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateGraphAnnotation updates a graph annotation.
+// https://mackerel.io/api-docs/entry/graph-annotation#update
+func (c *Client) UpdateGraphAnnotation(ctx context.Context, annotationID string, param *GraphAnnotation) (*GraphAnnotation, error) {
+	var ret GraphAnnotation
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v0/graph-annotations/%s", annotationID), param, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteGraphAnnotation deletes a graph annotation.
+// https://mackerel.io/api-docs/entry/graph-annotation#delete
+func (c *Client) DeleteGraphAnnotation(ctx context.Context, annotationID string) (*GraphAnnotation, error) {
+	var ret GraphAnnotation
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v0/graph-annotations/%s", annotationID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
@@ -0,0 +1,243 @@
+package mackerel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCreateGraphAnnotation(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPost, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-annotations" {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-annotations", r.URL.Path)
+		}
+
+		var data any
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&data); err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string]any{
+			"title":       "deploy",
+			"description": "my-stack is updated",
+			"from":        1484000000.0,
+			"to":          1484000030.0,
+			"service":     "ExampleService",
+			"roles":       []any{"ExampleRole1", "ExampleRole2"},
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("CreateGraphAnnotation differs: (-got +want)\n%s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"id":"2bj...","title":"deploy","description":"my-stack is updated","from":1484000000,"to":1484000030,"service":"ExampleService","roles":["ExampleRole1","ExampleRole2"]}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.CreateGraphAnnotation(context.Background(), &GraphAnnotation{
+		Title:       "deploy",
+		Description: "my-stack is updated",
+		From:        1484000000,
+		To:          1484000030,
+		Service:     "ExampleService",
+		Roles:       []string{"ExampleRole1", "ExampleRole2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GraphAnnotation{
+		ID:          "2bj...",
+		Title:       "deploy",
+		Description: "my-stack is updated",
+		From:        1484000000,
+		To:          1484000030,
+		Service:     "ExampleService",
+		Roles:       []string{"ExampleRole1", "ExampleRole2"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("CreateGraphAnnotation differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestUpdateGraphAnnotation(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPut, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-annotations/2bj..." {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-annotations/2bj...", r.URL.Path)
+		}
+
+		var data any
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&data); err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string]any{
+			"title":   "deploy",
+			"from":    1484000000.0,
+			"to":      1484000030.0,
+			"service": "ExampleService",
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("UpdateGraphAnnotation differs: (-got +want)\n%s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"id":"2bj...","title":"deploy","from":1484000000,"to":1484000030,"service":"ExampleService"}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.UpdateGraphAnnotation(context.Background(), "2bj...", &GraphAnnotation{
+		Title:   "deploy",
+		From:    1484000000,
+		To:      1484000030,
+		Service: "ExampleService",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GraphAnnotation{
+		ID:      "2bj...",
+		Title:   "deploy",
+		From:    1484000000,
+		To:      1484000030,
+		Service: "ExampleService",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("UpdateGraphAnnotation differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDeleteGraphAnnotation(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodDelete, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-annotations/2bj..." {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-annotations/2bj...", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"id":"2bj...","title":"deploy","from":1484000000,"to":1484000030,"service":"ExampleService"}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.DeleteGraphAnnotation(context.Background(), "2bj...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GraphAnnotation{
+		ID:      "2bj...",
+		Title:   "deploy",
+		From:    1484000000,
+		To:      1484000030,
+		Service: "ExampleService",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("DeleteGraphAnnotation differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestFindGraphAnnotations(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodGet, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-annotations" {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-annotations", r.URL.Path)
+		}
+		want := url.Values{
+			"service": {"ExampleService"},
+			"from":    {"1484000000"},
+			"to":      {"1484000030"},
+		}
+		if diff := cmp.Diff(r.URL.Query(), want); diff != "" {
+			t.Errorf("query differs: (-got +want)\n%s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"graphAnnotations":[{"id":"2bj...","title":"deploy","from":1484000000,"to":1484000030,"service":"ExampleService"}]}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.FindGraphAnnotations(context.Background(), "ExampleService", 1484000000, 1484000030)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*GraphAnnotation{
+		{
+			ID:      "2bj...",
+			Title:   "deploy",
+			From:    1484000000,
+			To:      1484000030,
+			Service: "ExampleService",
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("FindGraphAnnotations differs: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,59 @@
+package mackerel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// GraphDefinition is a graph definition of custom metrics.
+type GraphDefinition struct {
+	Name        string                   `json:"name"`
+	DisplayName string                   `json:"displayName,omitempty"`
+	Unit        string                   `json:"unit,omitempty"`
+	Metrics     []*GraphDefinitionMetric `json:"metrics"`
+}
+
+// GraphDefinitionMetric is a metric in a graph definition.
+type GraphDefinitionMetric struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	IsStacked   bool   `json:"isStacked"`
+}
+
+// CreateGraphDefinitions creates or updates graph definitions.
+// https://mackerel.io/api-docs/entry/host-metrics#post-graphdef
+func (c *Client) CreateGraphDefinitions(ctx context.Context, defs []*GraphDefinition) error {
+	var data struct {
+		Success bool `json:"success"`
+	}
+	_, err := c.do(ctx, http.MethodPost, "/api/v0/graph-defs/create", defs, &data)
+	if err != nil {
+		return err
+	}
+	if !data.Success {
+		return errors.New("mackerel: unexpected response")
+	}
+	return nil
+}
+
+// DeleteGraphDefinition deletes a graph definition.
+// https://mackerel.io/api-docs/entry/host-metrics#delete-graphdef
+func (c *Client) DeleteGraphDefinition(ctx context.Context, name string) error {
+	param := struct {
+		Name string `json:"name"`
+	}{
+		Name: name,
+	}
+	var data struct {
+		Success bool `json:"success"`
+	}
+	_, err := c.do(ctx, http.MethodDelete, "/api/v0/graph-defs", param, &data)
+	if err != nil {
+		return err
+	}
+	if !data.Success {
+		return errors.New("mackerel: unexpected response")
+	}
+	return nil
+}
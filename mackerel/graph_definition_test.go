@@ -0,0 +1,136 @@
+package mackerel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCreateGraphDefinitions(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPost, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-defs/create" {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-defs/create", r.URL.Path)
+		}
+
+		var data any
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&data); err != nil {
+			t.Error(err)
+			return
+		}
+		want := []any{
+			map[string]any{
+				"name":        "custom.cpu.foo",
+				"displayName": "CPU",
+				"unit":        "percentage",
+				"metrics": []any{
+					map[string]any{
+						"name":        "custom.cpu.foo.user",
+						"displayName": "CPU user",
+						"isStacked":   true,
+					},
+					map[string]any{
+						"name":      "custom.cpu.foo.system",
+						"isStacked": false,
+					},
+				},
+			},
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("CreateGraphDefinitions differs: (-got +want)\n%s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"success":true}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	err = c.CreateGraphDefinitions(context.Background(), []*GraphDefinition{
+		{
+			Name:        "custom.cpu.foo",
+			DisplayName: "CPU",
+			Unit:        "percentage",
+			Metrics: []*GraphDefinitionMetric{
+				{
+					Name:        "custom.cpu.foo.user",
+					DisplayName: "CPU user",
+					IsStacked:   true,
+				},
+				{
+					Name: "custom.cpu.foo.system",
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteGraphDefinition(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodDelete, r.Method)
+		}
+		if r.URL.Path != "/api/v0/graph-defs" {
+			t.Errorf("unexpected path, want %s, got %s", "/api/v0/graph-defs", r.URL.Path)
+		}
+
+		var data map[string]any
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&data); err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string]any{
+			"name": "custom.cpu.foo",
+		}
+		if diff := cmp.Diff(data, want); diff != "" {
+			t.Errorf("DeleteGraphDefinition differs: (-got +want)\n%s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, `{"success":true}`); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	err = c.DeleteGraphDefinition(context.Background(), "custom.cpu.foo")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -3,25 +3,49 @@ package mackerel
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 )
 
 // Host is host information
 type Host struct {
-	ID               string `json:"id"`
-	Name             string `json:"name"`
-	DisplayName      string `json:"displayName,omitempty"`
-	CustomIdentifier string `json:"customIdentifier,omitempty"`
-	Type             string `json:"type"`
-	Status           string `json:"status"`
-	Memo             string `json:"memo"`
-	// Roles            Roles       `json:"roles"`
-	IsRetired bool      `json:"isRetired"`
-	CreatedAt Timestamp `json:"createdAt"`
-	Meta      HostMeta  `json:"meta"`
-	// Interfaces       []Interface `json:"interfaces"`
+	ID               string      `json:"id"`
+	Name             string      `json:"name"`
+	DisplayName      string      `json:"displayName,omitempty"`
+	CustomIdentifier string      `json:"customIdentifier,omitempty"`
+	Type             string      `json:"type"`
+	Status           string      `json:"status"`
+	Memo             string      `json:"memo"`
+	Roles            Roles       `json:"roles"`
+	IsRetired        bool        `json:"isRetired"`
+	CreatedAt        Timestamp   `json:"createdAt"`
+	Meta             HostMeta    `json:"meta"`
+	Interfaces       []Interface `json:"interfaces"`
 }
 
+// Roles is the roles of the host, grouped by the service names.
+type Roles map[string][]string
+
+// Fullnames returns the full names of the roles, e.g. "service:role".
+func (r Roles) Fullnames() []string {
+	var fullnames []string
+	for _, serviceName := range slices.Sorted(maps.Keys(r)) {
+		for _, roleName := range r[serviceName] {
+			fullnames = append(fullnames, serviceName+":"+roleName)
+		}
+	}
+	return fullnames
+}
+
+// The statuses of hosts.
+const (
+	HostStatusWorking     = "working"
+	HostStatusStandby     = "standby"
+	HostStatusMaintenance = "maintenance"
+	HostStatusPoweroff    = "poweroff"
+)
+
 // HostMeta host meta information
 type HostMeta struct {
 	AgentRevision string `json:"agent-revision,omitempty"`
@@ -35,15 +59,31 @@ type HostMeta struct {
 	// Cloud         *Cloud      `json:"cloud,omitempty"`
 }
 
+// Interface is a network interface of the host.
+type Interface struct {
+	Name          string   `json:"name,omitempty"`
+	IPAddress     string   `json:"ipAddress,omitempty"`
+	IPv4Addresses []string `json:"ipv4Addresses,omitempty"`
+	IPv6Addresses []string `json:"ipv6Addresses,omitempty"`
+	MacAddress    string   `json:"macAddress,omitempty"`
+}
+
+// CheckConfig is a check monitoring item of the host.
+type CheckConfig struct {
+	Name string `json:"name"`
+	Memo string `json:"memo,omitempty"`
+}
+
 // CreateHostParam parameters for CreateHost
 type CreateHostParam struct {
-	Name        string   `json:"name"`
-	DisplayName string   `json:"displayName,omitempty"`
-	Meta        HostMeta `json:"meta"`
-	// Interfaces       []Interface   `json:"interfaces,omitempty"`
-	RoleFullnames []string `json:"roleFullnames,omitempty"`
-	// Checks           []CheckConfig `json:"checks,omitempty"`
-	CustomIdentifier string `json:"customIdentifier,omitempty"`
+	Name             string        `json:"name"`
+	DisplayName      string        `json:"displayName,omitempty"`
+	Memo             string        `json:"memo"`
+	Meta             HostMeta      `json:"meta"`
+	Interfaces       []Interface   `json:"interfaces,omitempty"`
+	RoleFullnames    []string      `json:"roleFullnames,omitempty"`
+	Checks           []CheckConfig `json:"checks,omitempty"`
+	CustomIdentifier string        `json:"customIdentifier,omitempty"`
 }
 
 // CreateHost creates new host
@@ -58,6 +98,18 @@ func (c *Client) CreateHost(ctx context.Context, param *CreateHostParam) (string
 	return data.ID, nil
 }
 
+// FindHost finds the host.
+func (c *Client) FindHost(ctx context.Context, hostID string) (*Host, error) {
+	var data struct {
+		Host *Host `json:"host"`
+	}
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v0/hosts/%s", hostID), nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data.Host, nil
+}
+
 // UpdateHostParam is parameters for UpdateHost
 type UpdateHostParam CreateHostParam
 
@@ -73,6 +125,28 @@ func (c *Client) UpdateHost(ctx context.Context, hostID string, param *UpdateHos
 	return data.ID, nil
 }
 
+// UpdateHostStatus changes the status of the host.
+func (c *Client) UpdateHostStatus(ctx context.Context, hostID, status string) error {
+	param := map[string]string{
+		"status": status,
+	}
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v0/hosts/%s/status", hostID), param, nil)
+	return err
+}
+
+// UpdateHostRoleFullnames replaces the roles of the host, without updating the other information.
+func (c *Client) UpdateHostRoleFullnames(ctx context.Context, hostID string, roleFullnames []string) error {
+	if roleFullnames == nil {
+		// the api requires an array, not null.
+		roleFullnames = []string{}
+	}
+	param := map[string][]string{
+		"roleFullnames": roleFullnames,
+	}
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v0/hosts/%s/role-fullnames", hostID), param, nil)
+	return err
+}
+
 // RetireHost make the host retired.
 func (c *Client) RetireHost(ctx context.Context, id string) error {
 	param := map[string]string{}
@@ -0,0 +1,132 @@
+package mackerel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateHostStatus(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPost, r.Method)
+		}
+		if r.URL.Path != "/api/v0/hosts/3yAYEDLXKL5/status" {
+			t.Errorf("unexpected path: want %s, got %s", "/api/v0/hosts/3yAYEDLXKL5/status", r.URL.Path)
+		}
+		var param map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&param); err != nil {
+			t.Error(err)
+		}
+		if param["status"] != HostStatusMaintenance {
+			t.Errorf("unexpected status: want %s, got %s", HostStatusMaintenance, param["status"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"success":true}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	if err := c.UpdateHostStatus(context.Background(), "3yAYEDLXKL5", HostStatusMaintenance); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFindHost(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodGet, r.Method)
+		}
+		if r.URL.Path != "/api/v0/hosts/3yAYEDLXKL5" {
+			t.Errorf("unexpected path: want %s, got %s", "/api/v0/hosts/3yAYEDLXKL5", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"host":{"id":"3yAYEDLXKL5","name":"host-foobar","status":"working","roles":{"service-b":["role"],"service-a":["role-x","role-y"]}}}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	h, err := c.FindHost(context.Background(), "3yAYEDLXKL5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Name != "host-foobar" {
+		t.Errorf("unexpected name: want %s, got %s", "host-foobar", h.Name)
+	}
+	want := []string{"service-a:role-x", "service-a:role-y", "service-b:role"}
+	if diff := cmp.Diff(want, h.Roles.Fullnames()); diff != "" {
+		t.Errorf("unexpected roles (-want/+got):\n%s", diff)
+	}
+}
+
+func TestUpdateHostRoleFullnames(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPut, r.Method)
+		}
+		if r.URL.Path != "/api/v0/hosts/3yAYEDLXKL5/role-fullnames" {
+			t.Errorf("unexpected path: want %s, got %s", "/api/v0/hosts/3yAYEDLXKL5/role-fullnames", r.URL.Path)
+		}
+		var param map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&param); err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(map[string]any{"roleFullnames": []any{}}, param); diff != "" {
+			t.Errorf("unexpected body (-want/+got):\n%s", diff)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"success":true}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	// removing all roles sends an empty array, not null.
+	if err := c.UpdateHostRoleFullnames(context.Background(), "3yAYEDLXKL5", nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -39,6 +40,20 @@ func (f APIKeyProviderFunc) MackerelAPIKey(ctx context.Context) (string, error)
 	return f(ctx)
 }
 
+// APIKeyRefresher is an optional interface of APIKeyProvider.
+// If RefreshesMackerelAPIKey returns true, Client doesn't cache the api key and asks the provider for each request,
+// so the provider can refresh the key, e.g. after rotation. Such providers should cache the key by themselves.
+type APIKeyRefresher interface {
+	RefreshesMackerelAPIKey() bool
+}
+
+// APIKeyInvalidator is an optional interface of APIKeyProvider.
+// Client calls InvalidateMackerelAPIKey when Mackerel rejects the api key from the provider,
+// so the provider that caches the key by itself can discard it.
+type APIKeyInvalidator interface {
+	InvalidateMackerelAPIKey()
+}
+
 // Client is a client for mackerel.io
 type Client struct {
 	BaseURL        *url.URL
@@ -47,8 +62,17 @@ type Client struct {
 	UserAgent      string
 	HTTPClient     *http.Client
 
-	mu     sync.RWMutex
-	apikey string // cached api key
+	// APIKeyTTL is the duration for caching the api key from APIKeyProvider.
+	// If it is zero, the api key is cached until Mackerel rejects it.
+	APIKeyTTL time.Duration
+
+	// RetryPolicy is the policy for retrying failed requests.
+	// If it is nil, DefaultRetryPolicy is used.
+	RetryPolicy *RetryPolicy
+
+	mu              sync.RWMutex
+	apikey          string    // cached api key
+	apikeyExpiresAt time.Time // the expiration of the cached api key, if APIKeyTTL is set
 }
 
 func (c *Client) httpClient() *http.Client {
@@ -68,7 +92,8 @@ func (c *Client) urlfor(path string) string {
 	u := new(url.URL)
 	*u = *base
 
-	u.Path = path
+	// the path may have the query string.
+	u.Path, u.RawQuery, _ = strings.Cut(path, "?")
 	return u.String()
 }
 
@@ -78,9 +103,15 @@ func (c *Client) getAPIKey(ctx context.Context) (string, error) {
 		return c.APIKey, nil
 	}
 
+	// the provider manages the lifetime of the api key
+	if r, ok := c.APIKeyProvider.(APIKeyRefresher); ok && r.RefreshesMackerelAPIKey() {
+		return c.APIKeyProvider.MackerelAPIKey(ctx)
+	}
+
 	// check cached api key
+	now := time.Now()
 	c.mu.RLock()
-	if c.apikey != "" {
+	if c.validAPIKey(now) {
 		key := c.apikey
 		c.mu.RUnlock()
 		return key, nil
@@ -90,7 +121,7 @@ func (c *Client) getAPIKey(ctx context.Context) (string, error) {
 	// need to update api key
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.apikey != "" {
+	if c.validAPIKey(now) {
 		return c.apikey, nil
 	}
 
@@ -103,9 +134,40 @@ func (c *Client) getAPIKey(ctx context.Context) (string, error) {
 		return "", err
 	}
 	c.apikey = apikey
+	if c.APIKeyTTL > 0 {
+		c.apikeyExpiresAt = now.Add(c.APIKeyTTL)
+	}
 	return apikey, nil
 }
 
+// validAPIKey reports whether the cached api key is available.
+// c.mu must be held.
+func (c *Client) validAPIKey(now time.Time) bool {
+	if c.apikey == "" {
+		return false
+	}
+	return c.APIKeyTTL <= 0 || now.Before(c.apikeyExpiresAt)
+}
+
+// invalidateAPIKey discards the api key from the provider.
+// It reports whether the api key may be changed by asking the provider again.
+func (c *Client) invalidateAPIKey() bool {
+	if c.APIKey != "" || c.APIKeyProvider == nil {
+		// the static api key never changes.
+		return false
+	}
+
+	c.mu.Lock()
+	c.apikey = ""
+	c.apikeyExpiresAt = time.Time{}
+	c.mu.Unlock()
+
+	if i, ok := c.APIKeyProvider.(APIKeyInvalidator); ok {
+		i.InvalidateMackerelAPIKey()
+	}
+	return true
+}
+
 func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
 	u := c.urlfor(path)
 	req, err := http.NewRequest(method, u, body)
@@ -128,33 +190,88 @@ func (c *Client) newRequest(ctx context.Context, method, path string, body io.Re
 	return req, nil
 }
 
-func (c *Client) do(ctx context.Context, method, path string, in, out any) (http.Header, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
 
-	var body io.Reader
+func (c *Client) do(ctx context.Context, method, path string, in, out any) (http.Header, error) {
+	var data []byte
 	if in != nil {
-		data, err := json.Marshal(in)
+		var err error
+		data, err = json.Marshal(in)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	policy := c.retryPolicy()
+	var reauthenticated bool
+	for attempt := 1; ; attempt++ {
+		h, statusCode, err := c.doOnce(ctx, method, path, data, out)
+		if err == nil {
+			return h, nil
+		}
+
+		// the api key may be rotated. ask the provider again once.
+		if !reauthenticated && isAuthError(statusCode) && ctx.Err() == nil && c.invalidateAPIKey() {
+			reauthenticated = true
+			attempt--
+			continue
+		}
+
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !isRetryable(method, statusCode) {
+			return h, err
+		}
+
+		delay := policy.backoff(attempt)
+		if retryAfter := parseRetryAfter(h, time.Now()); retryAfter > 0 {
+			delay = retryAfter
+		}
+
+		// give up if the next attempt would exceed the deadline.
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return h, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return h, err
+		case <-timer.C:
+		}
+	}
+}
+
+// doOnce sends a request without retrying.
+// It returns the status code of the response, zero if no response is received,
+// or -1 if the request is not sent.
+func (c *Client) doOnce(ctx context.Context, method, path string, data []byte, out any) (http.Header, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var body io.Reader
+	if data != nil {
 		body = bytes.NewReader(data)
 	}
 
 	req, err := c.newRequest(ctx, method, path, body)
 	if err != nil {
-		return nil, err
+		return nil, -1, err
 	}
 	req.Header.Add("Content-Type", "application/json")
 
 	resp, err := c.httpClient().Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close() //nolint:errcheck // ignore error because we can't do anything about it.
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return resp.Header, handleError(resp)
+		return resp.Header, resp.StatusCode, handleError(resp)
 	}
 
 	if out == nil {
@@ -163,11 +280,11 @@ func (c *Client) do(ctx context.Context, method, path string, in, out any) (http
 	} else {
 		dec := json.NewDecoder(resp.Body)
 		if err := dec.Decode(out); err != nil {
-			return nil, err
+			return nil, resp.StatusCode, err
 		}
 	}
 
-	return resp.Header, nil
+	return resp.Header, resp.StatusCode, nil
 }
 
 // Error is an error from the Mackerel.
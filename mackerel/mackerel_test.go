@@ -78,6 +78,39 @@ func TestDo(t *testing.T) {
 			t.Errorf("unexpected call count of APIKeyProvider: want %d, got %d", 1, got)
 		}
 	})
+
+	t.Run("refreshing api key", func(t *testing.T) {
+		var cnt int32
+		c := &Client{
+			BaseURL: u,
+			APIKeyProvider: &refreshingProvider{
+				APIKeyProviderFunc: func(ctx context.Context) (string, error) {
+					atomic.AddInt32(&cnt, 1)
+					return apiKey, nil
+				},
+			},
+			HTTPClient: ts.Client(),
+		}
+
+		for range 2 {
+			_, err := c.do(context.Background(), http.MethodGet, "/foo/bar", nil, nil)
+			if err != nil {
+				t.Error(err)
+			}
+		}
+
+		if got := atomic.LoadInt32(&cnt); got != 2 {
+			t.Errorf("unexpected call count of APIKeyProvider: want %d, got %d", 2, got)
+		}
+	})
+}
+
+type refreshingProvider struct {
+	APIKeyProviderFunc
+}
+
+func (p *refreshingProvider) RefreshesMackerelAPIKey() bool {
+	return true
 }
 
 func TestError(t *testing.T) {
@@ -114,3 +147,136 @@ func TestError(t *testing.T) {
 		t.Errorf("unexpected status code: want %d, got %d", http.StatusBadRequest, merr.StatusCode())
 	}
 }
+
+func TestDo_rotatedAPIKey(t *testing.T) {
+	var mu sync.Mutex
+	validKey := "old-api-key"
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("X-Api-Key") != validKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprintln(w, `{"error": {"message": "Authentication failed. Please try with valid Api Key."}}`)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `{}`)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotate := func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		validKey = key
+	}
+
+	t.Run("invalidate on authentication failure", func(t *testing.T) {
+		rotate("old-api-key")
+		var cnt int32
+		c := &Client{
+			BaseURL: u,
+			APIKeyProvider: APIKeyProviderFunc(func(ctx context.Context) (string, error) {
+				atomic.AddInt32(&cnt, 1)
+				mu.Lock()
+				defer mu.Unlock()
+				return validKey, nil
+			}),
+			HTTPClient: ts.Client(),
+		}
+		if _, err := c.do(context.Background(), http.MethodGet, "/foo/bar", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		rotate("new-api-key")
+		if _, err := c.do(context.Background(), http.MethodPost, "/foo/bar", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if got := atomic.LoadInt32(&cnt); got != 2 {
+			t.Errorf("unexpected call count of APIKeyProvider: want %d, got %d", 2, got)
+		}
+	})
+
+	t.Run("give up after reauthentication", func(t *testing.T) {
+		rotate("valid-api-key")
+		var cnt int32
+		c := &Client{
+			BaseURL: u,
+			APIKeyProvider: APIKeyProviderFunc(func(ctx context.Context) (string, error) {
+				atomic.AddInt32(&cnt, 1)
+				return "invalid-api-key", nil
+			}),
+			HTTPClient: ts.Client(),
+		}
+		_, err := c.do(context.Background(), http.MethodGet, "/foo/bar", nil, nil)
+		merr, ok := err.(Error)
+		if !ok {
+			t.Fatalf("want mackerel.Error, got %v", err)
+		}
+		if merr.StatusCode() != http.StatusUnauthorized {
+			t.Errorf("unexpected status code: want %d, got %d", http.StatusUnauthorized, merr.StatusCode())
+		}
+		if got := atomic.LoadInt32(&cnt); got != 2 {
+			t.Errorf("unexpected call count of APIKeyProvider: want %d, got %d", 2, got)
+		}
+	})
+
+	t.Run("static api key", func(t *testing.T) {
+		rotate("valid-api-key")
+		var cnt int32
+		ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&cnt, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		c := &Client{
+			BaseURL:    u,
+			APIKey:     "invalid-api-key",
+			HTTPClient: ts.Client(),
+		}
+		if _, err := c.do(context.Background(), http.MethodGet, "/foo/bar", nil, nil); err == nil {
+			t.Fatal("want error, got nil")
+		}
+		if got := atomic.LoadInt32(&cnt); got != 1 {
+			t.Errorf("unexpected request count: want %d, got %d", 1, got)
+		}
+	})
+}
+
+func TestGetAPIKey_ttl(t *testing.T) {
+	var cnt int32
+	c := &Client{
+		APIKeyProvider: APIKeyProviderFunc(func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("api-key-%d", atomic.AddInt32(&cnt, 1)), nil
+		}),
+		APIKeyTTL: time.Hour,
+	}
+
+	key, err := c.getAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "api-key-1" {
+		t.Errorf("unexpected api key: want %s, got %s", "api-key-1", key)
+	}
+
+	// the cached api key is used before it expires.
+	key, err = c.getAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "api-key-1" {
+		t.Errorf("unexpected api key: want %s, got %s", "api-key-1", key)
+	}
+
+	// the api key is refreshed after it expires.
+	c.apikeyExpiresAt = time.Now().Add(-time.Second)
+	key, err = c.getAPIKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "api-key-2" {
+		t.Errorf("unexpected api key: want %s, got %s", "api-key-2", key)
+	}
+}
@@ -0,0 +1,145 @@
+package mackereltest
+
+import (
+	"maps"
+	"net/http"
+	"strconv"
+)
+
+// awsIntegrationToJSON returns the integration in the form of the response of the api.
+// The secret key is never returned.
+func awsIntegrationToJSON(obj object) object {
+	if obj == nil {
+		return nil
+	}
+	obj = maps.Clone(obj)
+	delete(obj, "secretKey")
+	return obj
+}
+
+func (s *Server) validateAWSIntegration(id string, obj object) error {
+	if err := requireFields(obj, "name", "region"); err != nil {
+		return err
+	}
+
+	_, hasKey := obj["key"]
+	_, hasRoleArn := obj["roleArn"]
+	switch {
+	case hasKey && hasRoleArn:
+		return errorf(http.StatusBadRequest, "key and roleArn are exclusive")
+	case hasKey:
+		return requireFields(obj, "key", "secretKey")
+	case hasRoleArn:
+		if err := requireFields(obj, "roleArn", "externalId"); err != nil {
+			return err
+		}
+		if !s.externalIDs[stringField(obj, "externalId")] {
+			return errorf(http.StatusBadRequest, "unknown external id")
+		}
+		return nil
+	default:
+		return errorf(http.StatusBadRequest, "key or roleArn is required")
+	}
+}
+
+func (s *Server) handleFindAWSIntegrations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	integrations := []object{}
+	for _, obj := range s.awsIntegrations.list() {
+		integrations = append(integrations, awsIntegrationToJSON(obj))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"aws_integrations": integrations})
+}
+
+func (s *Server) handleFindAWSIntegration(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, err := s.awsIntegrations.get(r.PathValue("id"))
+	writeResult(w, awsIntegrationToJSON(obj), err)
+}
+
+func (s *Server) handleCreateAWSIntegration(w http.ResponseWriter, r *http.Request) {
+	var obj object
+	if err := decodeBody(r, &obj); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.validateAWSIntegration("", obj); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	obj = s.awsIntegrations.create(s.newID(), obj)
+	writeJSON(w, http.StatusOK, awsIntegrationToJSON(obj))
+}
+
+func (s *Server) handleUpdateAWSIntegration(w http.ResponseWriter, r *http.Request) {
+	var obj object
+	if err := decodeBody(r, &obj); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	old, err := s.awsIntegrations.get(id)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	// the secret key can be omitted when it is not changed.
+	if _, ok := obj["key"]; ok && obj["key"] == old["key"] {
+		if _, ok := obj["secretKey"]; !ok {
+			obj["secretKey"] = old["secretKey"]
+		}
+	}
+
+	if err := s.validateAWSIntegration(id, obj); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	obj, err = s.awsIntegrations.update(id, obj)
+	writeResult(w, awsIntegrationToJSON(obj), err)
+}
+
+func (s *Server) handleDeleteAWSIntegration(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, err := s.awsIntegrations.delete(r.PathValue("id"))
+	writeResult(w, awsIntegrationToJSON(obj), err)
+}
+
+func (s *Server) handleCreateAWSIntegrationExternalID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	externalID := s.org + "-" + strconv.Itoa(len(s.externalIDs)+1)
+	s.externalIDs[externalID] = true
+	writeJSON(w, http.StatusOK, map[string]any{"externalId": externalID})
+}
+
+// the excludable metrics of AWS integrations.
+// they are a part of the real list, but it is enough for testing.
+var excludableMetrics = map[string][]string{
+	"EC2": {
+		"ec2.cpu.used",
+		"ec2.network.in",
+		"ec2.network.out",
+	},
+	"ELB": {
+		"elb.count.request_count",
+		"elb.latency.latency",
+	},
+	"RDS": {
+		"rds.cpu.used",
+		"rds.memory.free",
+	},
+}
+
+func (s *Server) handleFindAWSIntegrationsExcludableMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, excludableMetrics)
+}
@@ -0,0 +1,184 @@
+package mackereltest
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// object is a JSON object stored in the server.
+type object = map[string]any
+
+// collection is a list of objects that have the "id" field, e.g. monitors, dashboards, etc.
+// The objects are listed in the order of creation.
+type collection struct {
+	name    string
+	ids     []string
+	objects map[string]object
+
+	// timestamps is true if the objects have the "createdAt" and "updatedAt" fields.
+	timestamps bool
+}
+
+func newCollection(name string) *collection {
+	return &collection{
+		name:    name,
+		objects: make(map[string]object),
+	}
+}
+
+func (c *collection) list() []object {
+	ret := make([]object, 0, len(c.ids))
+	for _, id := range c.ids {
+		ret = append(ret, c.objects[id])
+	}
+	return ret
+}
+
+func (c *collection) get(id string) (object, error) {
+	obj, ok := c.objects[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "%s not found", c.name)
+	}
+	return obj, nil
+}
+
+func (c *collection) create(id string, obj object) object {
+	obj = maps.Clone(obj)
+	obj["id"] = id
+	if c.timestamps {
+		now := time.Now().Unix()
+		obj["createdAt"] = now
+		obj["updatedAt"] = now
+	}
+	c.ids = append(c.ids, id)
+	c.objects[id] = obj
+	return obj
+}
+
+func (c *collection) update(id string, obj object) (object, error) {
+	old, err := c.get(id)
+	if err != nil {
+		return nil, err
+	}
+	obj = maps.Clone(obj)
+	obj["id"] = id
+	if c.timestamps {
+		obj["createdAt"] = old["createdAt"]
+		obj["updatedAt"] = time.Now().Unix()
+	}
+	c.objects[id] = obj
+	return obj, nil
+}
+
+func (c *collection) delete(id string) (object, error) {
+	obj, err := c.get(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(c.objects, id)
+	c.ids = slices.DeleteFunc(c.ids, func(v string) bool { return v == id })
+	return obj, nil
+}
+
+// validator validates the object before it is stored.
+// id is empty when the object is being created.
+// It is called with s.mu held.
+type validator func(id string, obj object) error
+
+// handleList returns a handler that lists the objects in the field named key.
+func (s *Server) handleList(c *collection, key string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{key: c.list()})
+	}
+}
+
+// handleGet returns a handler that gets the object.
+// If key is not empty, the object is wrapped in the field named key.
+func (s *Server) handleGet(c *collection, key string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		obj, err := c.get(r.PathValue("id"))
+		if err != nil || key == "" {
+			writeResult(w, obj, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{key: obj})
+	}
+}
+
+func (s *Server) handleCreate(c *collection, validate validator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var obj object
+		if err := decodeBody(r, &obj); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := validate("", obj); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, c.create(s.newID(), obj))
+	}
+}
+
+func (s *Server) handleUpdate(c *collection, validate validator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var obj object
+		if err := decodeBody(r, &obj); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := r.PathValue("id")
+		if _, err := c.get(id); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+		if err := validate(id, obj); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+		obj, err := c.update(id, obj)
+		writeResult(w, obj, err)
+	}
+}
+
+func (s *Server) handleDelete(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		obj, err := c.delete(r.PathValue("id"))
+		writeResult(w, obj, err)
+	}
+}
+
+// requireFields checks that the object has the non-empty fields.
+func requireFields(obj object, fields ...string) error {
+	for _, field := range fields {
+		switch v := obj[field].(type) {
+		case nil:
+			return errorf(http.StatusBadRequest, "%s is required", field)
+		case string:
+			if v == "" {
+				return errorf(http.StatusBadRequest, "%s is required", field)
+			}
+		}
+	}
+	return nil
+}
+
+// stringField returns the string field of the object.
+func stringField(obj object, field string) string {
+	s, _ := obj[field].(string)
+	return s
+}
@@ -0,0 +1,137 @@
+package mackereltest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+func (s *Server) validateDashboard(id string, obj object) error {
+	if err := requireFields(obj, "title", "urlPath"); err != nil {
+		return err
+	}
+
+	// the url paths must be unique in the organization.
+	urlPath := stringField(obj, "urlPath")
+	for _, d := range s.dashboards.list() {
+		if d["id"] != id && stringField(d, "urlPath") == urlPath {
+			return errorf(http.StatusBadRequest, "the url path is already used: %s", urlPath)
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleFindDashboards(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the list api doesn't return the widgets.
+	dashboards := []object{}
+	for _, d := range s.dashboards.list() {
+		d = maps.Clone(d)
+		delete(d, "widgets")
+		dashboards = append(dashboards, d)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"dashboards": dashboards})
+}
+
+// GraphDefinition returns the graph definition created through the api.
+func (s *Server) GraphDefinition(name string) (*mackerel.GraphDefinition, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.graphDefs[name]
+	if !ok {
+		return nil, false
+	}
+	var def mackerel.GraphDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		panic(err)
+	}
+	return &def, true
+}
+
+func (s *Server) handleCreateGraphDefinitions(w http.ResponseWriter, r *http.Request) {
+	var defs []json.RawMessage
+	if err := decodeBody(r, &defs); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	names := make([]string, 0, len(defs))
+	for _, def := range defs {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(def, &v); err != nil || v.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		names = append(names, v.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, def := range defs {
+		s.graphDefs[names[i]] = def
+	}
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (s *Server) handleDeleteGraphDefinition(w http.ResponseWriter, r *http.Request) {
+	var param struct {
+		Name string `json:"name"`
+	}
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.graphDefs[param.Name]; !ok {
+		writeError(w, http.StatusNotFound, "graph definition not found")
+		return
+	}
+	delete(s.graphDefs, param.Name)
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (s *Server) validateGraphAnnotation(id string, obj object) error {
+	if err := requireFields(obj, "title", "from", "to", "service"); err != nil {
+		return err
+	}
+	svc, err := s.service(stringField(obj, "service"))
+	if err != nil {
+		return errorf(http.StatusBadRequest, "service not found")
+	}
+	for _, role := range stringList(obj["roles"]) {
+		if _, err := svc.role(role); err != nil {
+			return errorf(http.StatusBadRequest, "role not found: %s", role)
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleFindGraphAnnotations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	service := q.Get("service")
+	from, err1 := strconv.ParseFloat(q.Get("from"), 64)
+	to, err2 := strconv.ParseFloat(q.Get("to"), 64)
+	if service == "" || err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "service, from and to are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	annotations := slices.DeleteFunc(s.graphAnnotations.list(), func(a object) bool {
+		afrom, _ := a["from"].(float64)
+		ato, _ := a["to"].(float64)
+		return stringField(a, "service") != service || ato < from || afrom > to
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"graphAnnotations": annotations})
+}
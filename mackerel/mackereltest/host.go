@@ -0,0 +1,209 @@
+package mackereltest
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+type host struct {
+	id            string
+	param         object
+	roleFullnames []string
+	status        string
+	isRetired     bool
+	createdAt     int64
+}
+
+func (s *Server) host(id string) (*host, error) {
+	h, ok := s.hosts[id]
+	if !ok || h.isRetired {
+		return nil, errorf(http.StatusNotFound, "host not found")
+	}
+	return h, nil
+}
+
+// toJSON returns the host in the form of the response of the api.
+func (s *Server) hostToJSON(h *host) object {
+	ret := maps.Clone(h.param)
+	ret["id"] = h.id
+	ret["status"] = h.status
+	ret["isRetired"] = h.isRetired
+	ret["createdAt"] = h.createdAt
+
+	// the roles that have been deleted are not shown.
+	roles := map[string][]string{}
+	for _, fullname := range h.roleFullnames {
+		serviceName, roleName, _ := strings.Cut(fullname, ":")
+		svc, err := s.service(serviceName)
+		if err != nil {
+			continue
+		}
+		if _, err := svc.role(roleName); err != nil {
+			continue
+		}
+		roles[serviceName] = append(roles[serviceName], roleName)
+	}
+	ret["roles"] = roles
+	return ret
+}
+
+// decodeHostParam decodes the request body of creating and updating hosts.
+func (s *Server) decodeHostParam(r *http.Request) (object, []string, error) {
+	var param object
+	if err := decodeBody(r, &param); err != nil {
+		return nil, nil, err
+	}
+	if err := requireFields(param, "name"); err != nil {
+		return nil, nil, err
+	}
+
+	var roleFullnames []string
+	if v, ok := param["roleFullnames"].([]any); ok {
+		for _, item := range v {
+			fullname, ok := item.(string)
+			if !ok {
+				return nil, nil, errorf(http.StatusBadRequest, "invalid role fullname: %v", item)
+			}
+			roleFullnames = append(roleFullnames, fullname)
+		}
+	}
+	delete(param, "roleFullnames")
+	return param, roleFullnames, nil
+}
+
+func (s *Server) handleCreateHost(w http.ResponseWriter, r *http.Request) {
+	param, roleFullnames, err := s.decodeHostParam(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fullname := range roleFullnames {
+		if err := s.ensureRole(fullname); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+	}
+	h := &host{
+		id:            s.newID(),
+		param:         param,
+		roleFullnames: roleFullnames,
+		status:        mackerel.HostStatusWorking,
+		createdAt:     time.Now().Unix(),
+	}
+	s.hosts[h.id] = h
+	writeJSON(w, http.StatusOK, map[string]any{"id": h.id})
+}
+
+func (s *Server) handleFindHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.hosts[r.PathValue("host")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"host": s.hostToJSON(h)})
+}
+
+func (s *Server) handleUpdateHost(w http.ResponseWriter, r *http.Request) {
+	param, roleFullnames, err := s.decodeHostParam(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, err := s.host(r.PathValue("host"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	for _, fullname := range roleFullnames {
+		if err := s.ensureRole(fullname); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+	}
+	h.param = param
+	h.roleFullnames = roleFullnames
+	writeJSON(w, http.StatusOK, map[string]any{"id": h.id})
+}
+
+func (s *Server) handleUpdateHostStatus(w http.ResponseWriter, r *http.Request) {
+	var param struct {
+		Status string `json:"status"`
+	}
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	switch param.Status {
+	case mackerel.HostStatusWorking, mackerel.HostStatusStandby, mackerel.HostStatusMaintenance, mackerel.HostStatusPoweroff:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, err := s.host(r.PathValue("host"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	h.status = param.Status
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (s *Server) handleUpdateHostRoleFullnames(w http.ResponseWriter, r *http.Request) {
+	var param struct {
+		RoleFullnames []string `json:"roleFullnames"`
+	}
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	if param.RoleFullnames == nil {
+		writeError(w, http.StatusBadRequest, "roleFullnames is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, err := s.host(r.PathValue("host"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	for _, fullname := range param.RoleFullnames {
+		if err := s.ensureRole(fullname); err != nil {
+			writeResult(w, nil, err)
+			return
+		}
+	}
+	h.roleFullnames = param.RoleFullnames
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (s *Server) handleRetireHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, err := s.host(r.PathValue("host"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	h.isRetired = true
+	s.deleteMetadata(func(key metadataKey) bool {
+		return key.host == h.id
+	})
+	writeJSON(w, http.StatusOK, success)
+}
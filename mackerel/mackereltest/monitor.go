@@ -0,0 +1,76 @@
+package mackereltest
+
+import (
+	"net/http"
+)
+
+var monitorTypes = map[string]bool{
+	"host":             true,
+	"connectivity":     true,
+	"service":          true,
+	"external":         true,
+	"expression":       true,
+	"anomalyDetection": true,
+	"query":            true,
+}
+
+func validateMonitor(id string, obj object) error {
+	if err := requireFields(obj, "type", "name"); err != nil {
+		return err
+	}
+	if typ := stringField(obj, "type"); !monitorTypes[typ] {
+		return errorf(http.StatusBadRequest, "invalid monitor type: %s", typ)
+	}
+	return nil
+}
+
+func validateChannel(id string, obj object) error {
+	return requireFields(obj, "type", "name")
+}
+
+func (s *Server) validateNotificationGroup(id string, obj object) error {
+	if err := requireFields(obj, "name"); err != nil {
+		return err
+	}
+
+	// the children must exist.
+	for _, id := range stringList(obj["childChannelIds"]) {
+		if _, err := s.channels.get(id); err != nil {
+			return errorf(http.StatusBadRequest, "channel not found: %s", id)
+		}
+	}
+	for _, id := range stringList(obj["childNotificationGroupIds"]) {
+		if _, err := s.notificationGroups.get(id); err != nil {
+			return errorf(http.StatusBadRequest, "notification group not found: %s", id)
+		}
+	}
+	monitors, _ := obj["monitors"].([]any)
+	for _, m := range monitors {
+		m, _ := m.(object)
+		id := stringField(m, "id")
+		if _, err := s.monitors.get(id); err != nil {
+			return errorf(http.StatusBadRequest, "monitor not found: %s", id)
+		}
+	}
+	return nil
+}
+
+func validateDowntime(id string, obj object) error {
+	return requireFields(obj, "name", "start", "duration")
+}
+
+func validateAlertGroupSetting(id string, obj object) error {
+	return requireFields(obj, "name")
+}
+
+// stringList returns the strings in the JSON list.
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	ret := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,333 @@
+// Package mackereltest provides an in-memory fake of the Mackerel API for testing.
+//
+// The server keeps the state of the organization,
+// so the objects created through the API can be read, updated and deleted later.
+//
+//	s := mackereltest.NewServer()
+//	defer s.Close()
+//	client := s.Client()
+//	client.CreateService(ctx, &mackerel.CreateServiceParam{Name: "my-service"})
+package mackereltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// DefaultAPIKey is the api key that the server accepts by default.
+const DefaultAPIKey = "mackereltest-api-key"
+
+// DefaultOrgName is the name of the organization by default.
+const DefaultOrgName = "mackereltest"
+
+// Server is an in-memory fake of the Mackerel API.
+type Server struct {
+	// URL is the base url of the server.
+	URL string
+
+	server *httptest.Server
+
+	mu     sync.Mutex
+	apiKey string
+	org    string
+	lastID uint64
+
+	services    map[string]*service
+	hosts       map[string]*host
+	metadata    map[metadataKey]*metadata
+	users       []*mackerel.User
+	invitations []*mackerel.Invitation
+	graphDefs   map[string]json.RawMessage
+	externalIDs map[string]bool
+
+	monitors           *collection
+	channels           *collection
+	notificationGroups *collection
+	downtimes          *collection
+	dashboards         *collection
+	awsIntegrations    *collection
+	graphAnnotations   *collection
+	alertGroupSettings *collection
+}
+
+// NewServer starts and returns a new server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		apiKey:      DefaultAPIKey,
+		org:         DefaultOrgName,
+		services:    make(map[string]*service),
+		hosts:       make(map[string]*host),
+		metadata:    make(map[metadataKey]*metadata),
+		graphDefs:   make(map[string]json.RawMessage),
+		externalIDs: make(map[string]bool),
+
+		monitors:           newCollection("monitor"),
+		channels:           newCollection("channel"),
+		notificationGroups: newCollection("notification group"),
+		downtimes:          newCollection("downtime"),
+		dashboards:         newCollection("dashboard"),
+		awsIntegrations:    newCollection("aws integration"),
+		graphAnnotations:   newCollection("graph annotation"),
+		alertGroupSettings: newCollection("alert group setting"),
+	}
+	s.dashboards.timestamps = true
+	s.server = httptest.NewServer(s.authenticate(s.routes()))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a new client that is configured to access the server.
+func (s *Server) Client() *mackerel.Client {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &mackerel.Client{
+		BaseURL:    u,
+		APIKey:     s.apiKey,
+		HTTPClient: s.server.Client(),
+	}
+}
+
+// SetAPIKey changes the api key that the server accepts.
+// It is useful for testing the rotation of api keys.
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// SetOrgName changes the name of the organization.
+func (s *Server) SetOrgName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.org = name
+}
+
+// AddUser adds a user to the organization.
+// Mackerel API doesn't provide the way to create users, so the tests use it instead.
+func (s *Server) AddUser(email string, authority mackerel.UserAuthority) *mackerel.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := &mackerel.User{
+		ID:                    s.newID(),
+		ScreenName:            email,
+		Email:                 email,
+		Authority:             authority,
+		AuthenticationMethods: []mackerel.UserAuthenticationMethod{mackerel.UserAuthenticationMethodPassword},
+		JoinedAt:              mackerel.Timestamp(time.Now().Unix()),
+	}
+	s.users = append(s.users, u)
+	return u
+}
+
+// the characters used in the ids of Mackerel.
+const idChars = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// newID returns a new id that looks like the ids of Mackerel, e.g. "2cSZzK3XfmG".
+// The ids are sequential, so the tests are reproducible.
+// The caller must hold s.mu.
+func (s *Server) newID() string {
+	s.lastID++
+	var buf [11]byte
+	n := s.lastID
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = idChars[n%uint64(len(idChars))]
+		n /= uint64(len(idChars))
+	}
+	return string(buf[:])
+}
+
+func (s *Server) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		apiKey := s.apiKey
+		s.mu.Unlock()
+		if r.Header.Get("X-Api-Key") != apiKey {
+			writeError(w, http.StatusUnauthorized, "Authentication failed. Please try again.")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	// org
+	mux.HandleFunc("GET /api/v0/org", s.handleGetOrg)
+
+	// services and roles
+	mux.HandleFunc("GET /api/v0/services", s.handleFindServices)
+	mux.HandleFunc("POST /api/v0/services", s.handleCreateService)
+	mux.HandleFunc("PUT /api/v0/services/{service}", s.handleUpdateService)
+	mux.HandleFunc("DELETE /api/v0/services/{service}", s.handleDeleteService)
+	mux.HandleFunc("GET /api/v0/services/{service}/roles", s.handleFindRoles)
+	mux.HandleFunc("POST /api/v0/services/{service}/roles", s.handleCreateRole)
+	mux.HandleFunc("PUT /api/v0/services/{service}/roles/{role}", s.handleUpdateRole)
+	mux.HandleFunc("DELETE /api/v0/services/{service}/roles/{role}", s.handleDeleteRole)
+
+	// hosts
+	mux.HandleFunc("POST /api/v0/hosts", s.handleCreateHost)
+	mux.HandleFunc("GET /api/v0/hosts/{host}", s.handleFindHost)
+	mux.HandleFunc("PUT /api/v0/hosts/{host}", s.handleUpdateHost)
+	mux.HandleFunc("POST /api/v0/hosts/{host}/status", s.handleUpdateHostStatus)
+	mux.HandleFunc("PUT /api/v0/hosts/{host}/role-fullnames", s.handleUpdateHostRoleFullnames)
+	mux.HandleFunc("POST /api/v0/hosts/{host}/retire", s.handleRetireHost)
+
+	// metadata
+	for _, prefix := range []string{
+		"/api/v0/hosts/{host}",
+		"/api/v0/services/{service}",
+		"/api/v0/services/{service}/roles/{role}",
+	} {
+		mux.HandleFunc("GET "+prefix+"/metadata", s.handleGetMetadataNamespaces)
+		mux.HandleFunc("GET "+prefix+"/metadata/{namespace}", s.handleGetMetadata)
+		mux.HandleFunc("PUT "+prefix+"/metadata/{namespace}", s.handlePutMetadata)
+		mux.HandleFunc("DELETE "+prefix+"/metadata/{namespace}", s.handleDeleteMetadata)
+	}
+
+	// monitors
+	mux.HandleFunc("GET /api/v0/monitors", s.handleList(s.monitors, "monitors"))
+	mux.HandleFunc("GET /api/v0/monitors/{id}", s.handleGet(s.monitors, "monitor"))
+	mux.HandleFunc("POST /api/v0/monitors", s.handleCreate(s.monitors, validateMonitor))
+	mux.HandleFunc("PUT /api/v0/monitors/{id}", s.handleUpdate(s.monitors, validateMonitor))
+	mux.HandleFunc("DELETE /api/v0/monitors/{id}", s.handleDelete(s.monitors))
+
+	// notification channels
+	mux.HandleFunc("GET /api/v0/channels", s.handleList(s.channels, "channels"))
+	mux.HandleFunc("POST /api/v0/channels", s.handleCreate(s.channels, validateChannel))
+	mux.HandleFunc("DELETE /api/v0/channels/{id}", s.handleDelete(s.channels))
+
+	// notification groups
+	mux.HandleFunc("GET /api/v0/notification-groups", s.handleList(s.notificationGroups, "notificationGroups"))
+	mux.HandleFunc("POST /api/v0/notification-groups", s.handleCreate(s.notificationGroups, s.validateNotificationGroup))
+	mux.HandleFunc("PUT /api/v0/notification-groups/{id}", s.handleUpdate(s.notificationGroups, s.validateNotificationGroup))
+	mux.HandleFunc("DELETE /api/v0/notification-groups/{id}", s.handleDelete(s.notificationGroups))
+
+	// users and invitations
+	mux.HandleFunc("GET /api/v0/users", s.handleFindUsers)
+	mux.HandleFunc("DELETE /api/v0/users/{id}", s.handleDeleteUser)
+	mux.HandleFunc("GET /api/v0/invitations", s.handleFindInvitations)
+	mux.HandleFunc("POST /api/v0/invitations", s.handleCreateInvitation)
+	mux.HandleFunc("POST /api/v0/invitations/revoke", s.handleRevokeInvitation)
+
+	// downtimes
+	mux.HandleFunc("GET /api/v0/downtimes", s.handleList(s.downtimes, "downtimes"))
+	mux.HandleFunc("POST /api/v0/downtimes", s.handleCreate(s.downtimes, validateDowntime))
+	mux.HandleFunc("PUT /api/v0/downtimes/{id}", s.handleUpdate(s.downtimes, validateDowntime))
+	mux.HandleFunc("DELETE /api/v0/downtimes/{id}", s.handleDelete(s.downtimes))
+
+	// dashboards
+	mux.HandleFunc("GET /api/v0/dashboards", s.handleFindDashboards)
+	mux.HandleFunc("GET /api/v0/dashboards/{id}", s.handleGet(s.dashboards, ""))
+	mux.HandleFunc("POST /api/v0/dashboards", s.handleCreate(s.dashboards, s.validateDashboard))
+	mux.HandleFunc("PUT /api/v0/dashboards/{id}", s.handleUpdate(s.dashboards, s.validateDashboard))
+	mux.HandleFunc("DELETE /api/v0/dashboards/{id}", s.handleDelete(s.dashboards))
+
+	// aws integrations
+	mux.HandleFunc("GET /api/v0/aws-integrations", s.handleFindAWSIntegrations)
+	mux.HandleFunc("GET /api/v0/aws-integrations/{id}", s.handleFindAWSIntegration)
+	mux.HandleFunc("POST /api/v0/aws-integrations", s.handleCreateAWSIntegration)
+	mux.HandleFunc("PUT /api/v0/aws-integrations/{id}", s.handleUpdateAWSIntegration)
+	mux.HandleFunc("DELETE /api/v0/aws-integrations/{id}", s.handleDeleteAWSIntegration)
+	mux.HandleFunc("POST /api/v0/aws-integrations-external-id", s.handleCreateAWSIntegrationExternalID)
+	mux.HandleFunc("GET /api/v0/aws-integrations-excludable-metrics", s.handleFindAWSIntegrationsExcludableMetrics)
+
+	// graph definitions and annotations
+	mux.HandleFunc("POST /api/v0/graph-defs/create", s.handleCreateGraphDefinitions)
+	mux.HandleFunc("DELETE /api/v0/graph-defs", s.handleDeleteGraphDefinition)
+	mux.HandleFunc("GET /api/v0/graph-annotations", s.handleFindGraphAnnotations)
+	mux.HandleFunc("POST /api/v0/graph-annotations", s.handleCreate(s.graphAnnotations, s.validateGraphAnnotation))
+	mux.HandleFunc("PUT /api/v0/graph-annotations/{id}", s.handleUpdate(s.graphAnnotations, s.validateGraphAnnotation))
+	mux.HandleFunc("DELETE /api/v0/graph-annotations/{id}", s.handleDelete(s.graphAnnotations))
+
+	// alert group settings
+	mux.HandleFunc("GET /api/v0/alert-group-settings", s.handleList(s.alertGroupSettings, "alertGroupSettings"))
+	mux.HandleFunc("GET /api/v0/alert-group-settings/{id}", s.handleGet(s.alertGroupSettings, ""))
+	mux.HandleFunc("POST /api/v0/alert-group-settings", s.handleCreate(s.alertGroupSettings, validateAlertGroupSetting))
+	mux.HandleFunc("PUT /api/v0/alert-group-settings/{id}", s.handleUpdate(s.alertGroupSettings, validateAlertGroupSetting))
+	mux.HandleFunc("DELETE /api/v0/alert-group-settings/{id}", s.handleDelete(s.alertGroupSettings))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "API not found")
+	})
+	return mux
+}
+
+func (s *Server) handleGetOrg(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, &mackerel.Org{Name: s.org})
+}
+
+// apiError is an error that is returned to the client.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, a ...any) error {
+	return &apiError{
+		status:  status,
+		message: fmt.Sprintf(format, a...),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	body.Error.Message = message
+	writeJSON(w, status, body)
+}
+
+// writeResult writes v if err is nil, otherwise writes the error.
+func writeResult(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		if e, ok := err.(*apiError); ok {
+			writeError(w, e.status, e.message)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON: %v", err)
+	}
+	return nil
+}
+
+var success = map[string]bool{"success": true}
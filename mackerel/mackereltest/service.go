@@ -0,0 +1,361 @@
+package mackereltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+type service struct {
+	name  string
+	memo  string
+	roles []*mackerel.Role
+}
+
+func (svc *service) toMackerel() *mackerel.Service {
+	roles := make([]string, 0, len(svc.roles))
+	for _, r := range svc.roles {
+		roles = append(roles, r.Name)
+	}
+	return &mackerel.Service{
+		Name:  svc.name,
+		Memo:  svc.memo,
+		Roles: roles,
+	}
+}
+
+func (svc *service) role(name string) (*mackerel.Role, error) {
+	i := slices.IndexFunc(svc.roles, func(r *mackerel.Role) bool { return r.Name == name })
+	if i < 0 {
+		return nil, errorf(http.StatusNotFound, "role not found")
+	}
+	return svc.roles[i], nil
+}
+
+// the names of services and roles.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_]{1,62}$`)
+
+func (s *Server) service(name string) (*service, error) {
+	svc, ok := s.services[name]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "service not found")
+	}
+	return svc, nil
+}
+
+// serviceNames returns the names of the services in the alphabetical order.
+func (s *Server) serviceNames() []string {
+	names := make([]string, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (s *Server) handleFindServices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	services := make([]*mackerel.Service, 0, len(s.services))
+	for _, name := range s.serviceNames() {
+		services = append(services, s.services[name].toMackerel())
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"services": services})
+}
+
+func (s *Server) handleCreateService(w http.ResponseWriter, r *http.Request) {
+	var param mackerel.CreateServiceParam
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !namePattern.MatchString(param.Name) {
+		writeError(w, http.StatusBadRequest, "invalid service name")
+		return
+	}
+	if _, ok := s.services[param.Name]; ok {
+		writeError(w, http.StatusBadRequest, "service already exists")
+		return
+	}
+	svc := &service{
+		name: param.Name,
+		memo: param.Memo,
+	}
+	s.services[param.Name] = svc
+	writeJSON(w, http.StatusOK, svc.toMackerel())
+}
+
+func (s *Server) handleUpdateService(w http.ResponseWriter, r *http.Request) {
+	var param mackerel.UpdateServiceParam
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	svc.memo = param.Memo
+	writeJSON(w, http.StatusOK, svc.toMackerel())
+}
+
+func (s *Server) handleDeleteService(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	delete(s.services, svc.name)
+	s.deleteMetadata(func(key metadataKey) bool {
+		return key.service == svc.name
+	})
+	writeJSON(w, http.StatusOK, svc.toMackerel())
+}
+
+func (s *Server) handleFindRoles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"roles": svc.roles})
+}
+
+func (s *Server) handleCreateRole(w http.ResponseWriter, r *http.Request) {
+	var param mackerel.CreateRoleParam
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	if !namePattern.MatchString(param.Name) {
+		writeError(w, http.StatusBadRequest, "invalid role name")
+		return
+	}
+	if _, err := svc.role(param.Name); err == nil {
+		writeError(w, http.StatusBadRequest, "role already exists")
+		return
+	}
+	role := &mackerel.Role{
+		Name: param.Name,
+		Memo: param.Memo,
+	}
+	svc.roles = append(svc.roles, role)
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) handleUpdateRole(w http.ResponseWriter, r *http.Request) {
+	var param mackerel.UpdateRoleParam
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	role, err := svc.role(r.PathValue("role"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	role.Memo = param.Memo
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.service(r.PathValue("service"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	role, err := svc.role(r.PathValue("role"))
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	svc.roles = slices.DeleteFunc(svc.roles, func(v *mackerel.Role) bool { return v == role })
+	s.deleteMetadata(func(key metadataKey) bool {
+		return key.service == svc.name && key.role == role.Name
+	})
+	writeJSON(w, http.StatusOK, role)
+}
+
+// ensureRole creates the service and the role if they don't exist.
+// The roles of hosts are created implicitly as mackerel-agent does.
+func (s *Server) ensureRole(fullname string) error {
+	serviceName, roleName, ok := strings.Cut(fullname, ":")
+	if !ok || !namePattern.MatchString(serviceName) || !namePattern.MatchString(roleName) {
+		return errorf(http.StatusBadRequest, "invalid role fullname: %s", fullname)
+	}
+	svc, ok := s.services[serviceName]
+	if !ok {
+		svc = &service{name: serviceName}
+		s.services[serviceName] = svc
+	}
+	if _, err := svc.role(roleName); err != nil {
+		svc.roles = append(svc.roles, &mackerel.Role{Name: roleName})
+	}
+	return nil
+}
+
+// metadataKey identifies the metadata.
+// host is set for the host metadata, service (and role) is set for the service (role) metadata.
+type metadataKey struct {
+	host      string
+	service   string
+	role      string
+	namespace string
+}
+
+type metadata struct {
+	value        json.RawMessage
+	lastModified time.Time
+}
+
+// metadataKey returns the key of the metadata for the request.
+// It returns an error if the target of the metadata doesn't exist.
+func (s *Server) metadataKey(r *http.Request) (metadataKey, error) {
+	key := metadataKey{
+		host:      r.PathValue("host"),
+		service:   r.PathValue("service"),
+		role:      r.PathValue("role"),
+		namespace: r.PathValue("namespace"),
+	}
+	if key.host != "" {
+		if _, err := s.host(key.host); err != nil {
+			return key, err
+		}
+		return key, nil
+	}
+	svc, err := s.service(key.service)
+	if err != nil {
+		return key, err
+	}
+	if key.role != "" {
+		if _, err := svc.role(key.role); err != nil {
+			return key, err
+		}
+	}
+	return key, nil
+}
+
+func (s *Server) deleteMetadata(f func(key metadataKey) bool) {
+	for key := range s.metadata {
+		if f(key) {
+			delete(s.metadata, key)
+		}
+	}
+}
+
+func (s *Server) handleGetMetadataNamespaces(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, err := s.metadataKey(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	var namespaces []string
+	for key := range s.metadata {
+		ns := key.namespace
+		key.namespace = ""
+		if key == target {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	slices.Sort(namespaces)
+
+	type item struct {
+		Namespace string `json:"namespace"`
+	}
+	items := make([]item, 0, len(namespaces))
+	for _, ns := range namespaces {
+		items = append(items, item{Namespace: ns})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"metadata": items})
+}
+
+func (s *Server) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, err := s.metadataKey(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	m, ok := s.metadata[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "metadata not found")
+		return
+	}
+	w.Header().Set("Last-Modified", m.lastModified.Format(http.TimeFormat))
+	writeJSON(w, http.StatusOK, m.value)
+}
+
+func (s *Server) handlePutMetadata(w http.ResponseWriter, r *http.Request) {
+	var value json.RawMessage
+	if err := decodeBody(r, &value); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, err := s.metadataKey(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	s.metadata[key] = &metadata{
+		value:        value,
+		lastModified: time.Now(),
+	}
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (s *Server) handleDeleteMetadata(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, err := s.metadataKey(r)
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	if _, ok := s.metadata[key]; !ok {
+		writeError(w, http.StatusNotFound, "metadata not found")
+		return
+	}
+	delete(s.metadata, key)
+	writeJSON(w, http.StatusOK, success)
+}
@@ -0,0 +1,109 @@
+package mackereltest
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shogo82148/cfn-mackerel-macro/mackerel"
+)
+
+// invitations expire in a week.
+const invitationTTL = 7 * 24 * time.Hour
+
+func (s *Server) handleFindUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := s.users
+	if users == nil {
+		users = []*mackerel.User{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"users": users})
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	i := slices.IndexFunc(s.users, func(u *mackerel.User) bool { return u.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	user := s.users[i]
+	if user.Authority == mackerel.UserAuthorityOwner {
+		writeError(w, http.StatusBadRequest, "the owner cannot be deleted")
+		return
+	}
+	s.users = slices.Delete(s.users, i, i+1)
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleFindInvitations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	invitations := s.invitations
+	if invitations == nil {
+		invitations = []*mackerel.Invitation{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"invitations": invitations})
+}
+
+func (s *Server) handleCreateInvitation(w http.ResponseWriter, r *http.Request) {
+	var param struct {
+		Email     string                 `json:"email"`
+		Authority mackerel.UserAuthority `json:"authority"`
+	}
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if param.Email == "" {
+		writeError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+	switch param.Authority {
+	case mackerel.UserAuthorityManager, mackerel.UserAuthorityCollaborator, mackerel.UserAuthorityViewer:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid authority")
+		return
+	}
+	if slices.ContainsFunc(s.users, func(u *mackerel.User) bool { return u.Email == param.Email }) {
+		writeError(w, http.StatusBadRequest, "the user already belongs to the organization")
+		return
+	}
+	if slices.ContainsFunc(s.invitations, func(inv *mackerel.Invitation) bool { return inv.Email == param.Email }) {
+		writeError(w, http.StatusBadRequest, "the user is already invited")
+		return
+	}
+	invitation := &mackerel.Invitation{
+		Email:     param.Email,
+		Authority: param.Authority,
+		ExpiresAt: mackerel.Timestamp(time.Now().Add(invitationTTL).Unix()),
+	}
+	s.invitations = append(s.invitations, invitation)
+	writeJSON(w, http.StatusOK, invitation)
+}
+
+func (s *Server) handleRevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	var param struct {
+		Email string `json:"email"`
+	}
+	if err := decodeBody(r, &param); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.invitations, func(inv *mackerel.Invitation) bool { return inv.Email == param.Email })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "invitation not found")
+		return
+	}
+	s.invitations = slices.Delete(s.invitations, i, i+1)
+	writeJSON(w, http.StatusOK, success)
+}
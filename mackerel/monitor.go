@@ -14,6 +14,7 @@ type Monitor interface {
 	MonitorType() MonitorType
 	MonitorID() string
 	MonitorName() string
+	MonitorMemo() string
 }
 
 // MonitorType is a type of monitors.
@@ -37,6 +38,12 @@ const (
 
 	// MonitorTypeAnomalyDetection is a type for anomaly detection.
 	MonitorTypeAnomalyDetection MonitorType = "anomalyDetection"
+
+	// MonitorTypeCheck is a type for check monitoring.
+	MonitorTypeCheck MonitorType = "check"
+
+	// MonitorTypeQuery is a type for query monitoring.
+	MonitorTypeQuery MonitorType = "query"
 )
 
 func (t MonitorType) String() string {
@@ -67,6 +74,10 @@ func (m *monitor) UnmarshalJSON(b []byte) error {
 		m.Monitor = &MonitorExpression{}
 	case MonitorTypeAnomalyDetection:
 		m.Monitor = &MonitorAnomalyDetection{}
+	case MonitorTypeCheck:
+		m.Monitor = &MonitorCheck{}
+	case MonitorTypeQuery:
+		m.Monitor = &MonitorQuery{}
 	default:
 		return fmt.Errorf("unknown monitor type: %s", data.Type)
 	}
@@ -95,6 +106,9 @@ func (m *MonitorConnectivity) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorConnectivity) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorConnectivity) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshaler.
 func (m *MonitorConnectivity) UnmarshalJSON(b []byte) error {
 	type monitor MonitorConnectivity
@@ -143,6 +157,9 @@ func (m *MonitorHostMetric) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorHostMetric) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorHostMetric) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshal.
 func (m *MonitorHostMetric) UnmarshalJSON(b []byte) error {
 	type monitor MonitorHostMetric
@@ -192,6 +209,9 @@ func (m *MonitorServiceMetric) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorServiceMetric) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorServiceMetric) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshaler.
 func (m *MonitorServiceMetric) UnmarshalJSON(b []byte) error {
 	type monitor MonitorServiceMetric
@@ -254,6 +274,9 @@ func (m *MonitorExternalHTTP) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorExternalHTTP) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorExternalHTTP) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshaler.
 func (m *MonitorExternalHTTP) UnmarshalJSON(b []byte) error {
 	type monitor MonitorExternalHTTP
@@ -297,6 +320,9 @@ func (m *MonitorExpression) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorExpression) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorExpression) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshaler.
 func (m *MonitorExpression) UnmarshalJSON(b []byte) error {
 	type monitor MonitorExpression
@@ -355,6 +381,9 @@ func (m *MonitorAnomalyDetection) MonitorName() string { return m.Name }
 // MonitorID returns monitor id.
 func (m *MonitorAnomalyDetection) MonitorID() string { return m.ID }
 
+// MonitorMemo returns monitor memo.
+func (m *MonitorAnomalyDetection) MonitorMemo() string { return m.Memo }
+
 // UnmarshalJSON implements json.Unmarshaler.
 func (m *MonitorAnomalyDetection) UnmarshalJSON(b []byte) error {
 	type monitor MonitorAnomalyDetection
@@ -374,16 +403,111 @@ func (m *MonitorAnomalyDetection) MarshalJSON() ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// MonitorCheck represents check monitor.
+type MonitorCheck struct {
+	ID                   string      `json:"id,omitempty"`
+	Name                 string      `json:"name,omitempty"`
+	Memo                 string      `json:"memo,omitempty"`
+	Type                 MonitorType `json:"type,omitempty"`
+	IsMute               bool        `json:"isMute,omitempty"`
+	NotificationInterval uint64      `json:"notificationInterval,omitempty"`
+
+	MaxCheckAttempts uint64 `json:"maxCheckAttempts,omitempty"`
+
+	Scopes        []string `json:"scopes,omitempty"`
+	ExcludeScopes []string `json:"excludeScopes,omitempty"`
+}
+
+// MonitorType returns monitor type.
+func (m *MonitorCheck) MonitorType() MonitorType { return MonitorTypeCheck }
+
+// MonitorName returns monitor name.
+func (m *MonitorCheck) MonitorName() string { return m.Name }
+
+// MonitorID returns monitor id.
+func (m *MonitorCheck) MonitorID() string { return m.ID }
+
+// MonitorMemo returns monitor memo.
+func (m *MonitorCheck) MonitorMemo() string { return m.Memo }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *MonitorCheck) UnmarshalJSON(b []byte) error {
+	type monitor MonitorCheck
+	data := (*monitor)(m)
+	if err := json.Unmarshal(b, data); err != nil {
+		return err
+	}
+	m.Type = MonitorTypeCheck
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *MonitorCheck) MarshalJSON() ([]byte, error) {
+	type monitor MonitorCheck
+	data := (*monitor)(m)
+	data.Type = MonitorTypeCheck
+	return json.Marshal(data)
+}
+
+// MonitorQuery represents query monitor.
+type MonitorQuery struct {
+	ID                   string      `json:"id,omitempty"`
+	Name                 string      `json:"name,omitempty"`
+	Memo                 string      `json:"memo,omitempty"`
+	Type                 MonitorType `json:"type,omitempty"`
+	IsMute               bool        `json:"isMute,omitempty"`
+	NotificationInterval uint64      `json:"notificationInterval,omitempty"`
+
+	Query    string   `json:"query,omitempty"`
+	Legend   string   `json:"legend,omitempty"`
+	Operator string   `json:"operator,omitempty"`
+	Warning  *float64 `json:"warning"`
+	Critical *float64 `json:"critical"`
+}
+
+// MonitorType returns monitor type.
+func (m *MonitorQuery) MonitorType() MonitorType { return MonitorTypeQuery }
+
+// MonitorName returns monitor name.
+func (m *MonitorQuery) MonitorName() string { return m.Name }
+
+// MonitorID returns monitor id.
+func (m *MonitorQuery) MonitorID() string { return m.ID }
+
+// MonitorMemo returns monitor memo.
+func (m *MonitorQuery) MonitorMemo() string { return m.Memo }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *MonitorQuery) UnmarshalJSON(b []byte) error {
+	type monitor MonitorQuery
+	data := (*monitor)(m)
+	if err := json.Unmarshal(b, data); err != nil {
+		return err
+	}
+	m.Type = MonitorTypeQuery
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *MonitorQuery) MarshalJSON() ([]byte, error) {
+	type monitor MonitorQuery
+	data := (*monitor)(m)
+	data.Type = MonitorTypeQuery
+	return json.Marshal(data)
+}
+
 // FindMonitors returns monitoring settings.
 func (c *Client) FindMonitors(ctx context.Context) ([]Monitor, error) {
-	var resp []monitor
+	var resp struct {
+		Monitors []monitor `json:"monitors"`
+	}
 	_, err := c.do(ctx, http.MethodGet, "/api/v0/monitors", nil, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	ret := make([]Monitor, 0, len(resp))
-	for _, m := range resp {
+	ret := make([]Monitor, 0, len(resp.Monitors))
+	for _, m := range resp.Monitors {
 		ret = append(ret, m.Monitor)
 	}
 	return ret, nil
@@ -391,12 +515,14 @@ func (c *Client) FindMonitors(ctx context.Context) ([]Monitor, error) {
 
 // FindMonitor returns a monitoring setting.
 func (c *Client) FindMonitor(ctx context.Context, monitorID string) (Monitor, error) {
-	var resp monitor
+	var resp struct {
+		Monitor monitor `json:"monitor"`
+	}
 	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v0/monitors/%s", monitorID), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Monitor, nil
+	return resp.Monitor.Monitor, nil
 }
 
 // CreateMonitor creates a new monitoring.
@@ -20,6 +20,8 @@ var (
 	_ Monitor = (*MonitorExternalHTTP)(nil)
 	_ Monitor = (*MonitorExpression)(nil)
 	_ Monitor = (*MonitorAnomalyDetection)(nil)
+	_ Monitor = (*MonitorCheck)(nil)
+	_ Monitor = (*MonitorQuery)(nil)
 )
 
 func TestFindMonitors(t *testing.T) {
@@ -218,6 +220,56 @@ func TestFindMonitors(t *testing.T) {
 				MaxCheckAttempts:   3,
 			},
 		},
+		{
+			resp: map[string]any{
+				"id":                   "2cSZzK3XfmG",
+				"type":                 "check",
+				"name":                 "check-cron",
+				"memo":                 "check the cron daemon",
+				"scopes":               []any{"myService:myRole"},
+				"excludeScopes":        []any{"myService:myRole2"},
+				"notificationInterval": 60,
+				"maxCheckAttempts":     3,
+				"isMute":               true,
+			},
+			want: &MonitorCheck{
+				ID:                   "2cSZzK3XfmG",
+				Name:                 "check-cron",
+				Memo:                 "check the cron daemon",
+				Type:                 MonitorTypeCheck,
+				IsMute:               true,
+				NotificationInterval: 60,
+				MaxCheckAttempts:     3,
+				Scopes:               []string{"myService:myRole"},
+				ExcludeScopes:        []string{"myService:myRole2"},
+			},
+		},
+		{
+			resp: map[string]any{
+				"id":                   "2cSZzK3XfmG",
+				"type":                 "query",
+				"name":                 "http latency",
+				"memo":                 "p99 latency of my service",
+				"query":                "histogram_quantile(0.99, http.server.duration)",
+				"legend":               "{{service.name}}",
+				"operator":             ">",
+				"warning":              0.5,
+				"critical":             1.0,
+				"notificationInterval": 60,
+			},
+			want: &MonitorQuery{
+				ID:                   "2cSZzK3XfmG",
+				Name:                 "http latency",
+				Memo:                 "p99 latency of my service",
+				Type:                 MonitorTypeQuery,
+				NotificationInterval: 60,
+				Query:                "histogram_quantile(0.99, http.server.duration)",
+				Legend:               "{{service.name}}",
+				Operator:             ">",
+				Warning:              ptrFloat64(0.5),
+				Critical:             ptrFloat64(1.0),
+			},
+		},
 	}
 
 	for i, tc := range tests {
@@ -232,7 +284,7 @@ func TestFindMonitors(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 				enc := json.NewEncoder(w)
-				if err := enc.Encode([]any{tc.resp}); err != nil {
+				if err := enc.Encode(map[string]any{"monitors": []any{tc.resp}}); err != nil {
 					t.Error(err)
 				}
 			}))
@@ -268,7 +320,7 @@ func TestFindMonitors(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 				enc := json.NewEncoder(w)
-				if err := enc.Encode(tc.resp); err != nil {
+				if err := enc.Encode(map[string]any{"monitor": tc.resp}); err != nil {
 					t.Error(err)
 				}
 			}))
@@ -509,6 +561,48 @@ func TestCreateMonitor(t *testing.T) {
 				"maxCheckAttempts":   3.0,
 			},
 		},
+		{
+			in: &MonitorCheck{
+				Name:                 "check-cron",
+				Memo:                 "check the cron daemon",
+				NotificationInterval: 60,
+				MaxCheckAttempts:     3,
+				Scopes:               []string{"myService:myRole"},
+				ExcludeScopes:        []string{"myService:myRole2"},
+			},
+			want: map[string]any{
+				"type":                 "check",
+				"name":                 "check-cron",
+				"memo":                 "check the cron daemon",
+				"notificationInterval": 60.0,
+				"maxCheckAttempts":     3.0,
+				"scopes":               []any{"myService:myRole"},
+				"excludeScopes":        []any{"myService:myRole2"},
+			},
+		},
+		{
+			in: &MonitorQuery{
+				Name:                 "http latency",
+				Memo:                 "p99 latency of my service",
+				Query:                "histogram_quantile(0.99, http.server.duration)",
+				Legend:               "{{service.name}}",
+				Operator:             ">",
+				Warning:              ptrFloat64(0.5),
+				Critical:             nil,
+				NotificationInterval: 60,
+			},
+			want: map[string]any{
+				"type":                 "query",
+				"name":                 "http latency",
+				"memo":                 "p99 latency of my service",
+				"query":                "histogram_quantile(0.99, http.server.duration)",
+				"legend":               "{{service.name}}",
+				"operator":             ">",
+				"warning":              0.5,
+				"critical":             nil,
+				"notificationInterval": 60.0,
+			},
+		},
 	}
 
 	for i, tc := range tests {
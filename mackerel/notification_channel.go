@@ -32,7 +32,7 @@ const (
 	// NotificationChannelTypeLine is LINE type.
 	NotificationChannelTypeLine NotificationChannelType = "line"
 
-	// NotificationChannelTypeWebHook is Chatwork type.
+	// NotificationChannelTypeChatwork is Chatwork type.
 	NotificationChannelTypeChatwork NotificationChannelType = "chatwork"
 
 	// NotificationChannelTypeTypetalk is Typetalk type.
@@ -41,13 +41,13 @@ const (
 	// NotificationChannelTypeHipchat is Hipchat type.
 	NotificationChannelTypeHipchat NotificationChannelType = "hipchat"
 
-	// NotificationChannelTypeHipchat is twilio type.
+	// NotificationChannelTypeTwilio is Twilio type.
 	NotificationChannelTypeTwilio NotificationChannelType = "twilio"
 
-	// NotificationChannelTypeReactio is Hipchat type.
+	// NotificationChannelTypeReactio is Reactio type.
 	NotificationChannelTypeReactio NotificationChannelType = "reactio"
 
-	// NotificationChannelTypePagerduty is Hipchat type.
+	// NotificationChannelTypePagerduty is PagerDuty type.
 	NotificationChannelTypePagerduty NotificationChannelType = "pagerduty"
 
 	// NotificationChannelTypeOpsgenie is Opsgenie type.
@@ -56,7 +56,7 @@ const (
 	// NotificationChannelTypeYammer is Yammer type.
 	NotificationChannelTypeYammer NotificationChannelType = "yammer"
 
-	// NotificationChannelTypeMicrosoftTeams is Hipchat type.
+	// NotificationChannelTypeMicrosoftTeams is Microsoft Teams type.
 	NotificationChannelTypeMicrosoftTeams NotificationChannelType = "microsoft-teams"
 
 	// NotificationChannelTypeAmazonEventBridge is Amazon Event Bridge type.
@@ -276,6 +276,363 @@ func (c *NotificationChannelWebHook) MarshalJSON() ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// NotificationChannelLine is a LINE notification channel.
+type NotificationChannelLine struct {
+	Type   NotificationChannelType `json:"type"`
+	ID     string                  `json:"id,omitempty"`
+	Name   string                  `json:"name"`
+	Token  string                  `json:"token"`
+	Events []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeLine
+func (c *NotificationChannelLine) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeLine
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelLine) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelLine) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelLine) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelLine
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeLine
+	*c = NotificationChannelLine(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelLine) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelLine
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeLine
+	return json.Marshal(data)
+}
+
+// NotificationChannelChatwork is a Chatwork notification channel.
+type NotificationChannelChatwork struct {
+	Type   NotificationChannelType `json:"type"`
+	ID     string                  `json:"id,omitempty"`
+	Name   string                  `json:"name"`
+	Token  string                  `json:"token"`
+	RoomID string                  `json:"roomId"`
+	Events []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeChatwork
+func (c *NotificationChannelChatwork) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeChatwork
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelChatwork) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelChatwork) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelChatwork) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelChatwork
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeChatwork
+	*c = NotificationChannelChatwork(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelChatwork) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelChatwork
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeChatwork
+	return json.Marshal(data)
+}
+
+// NotificationChannelTypetalk is a Typetalk notification channel.
+type NotificationChannelTypetalk struct {
+	Type   NotificationChannelType `json:"type"`
+	ID     string                  `json:"id,omitempty"`
+	Name   string                  `json:"name"`
+	URL    string                  `json:"url"`
+	Events []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeTypetalk
+func (c *NotificationChannelTypetalk) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeTypetalk
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelTypetalk) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelTypetalk) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelTypetalk) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelTypetalk
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeTypetalk
+	*c = NotificationChannelTypetalk(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelTypetalk) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelTypetalk
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeTypetalk
+	return json.Marshal(data)
+}
+
+// NotificationChannelTwilio is a Twilio notification channel.
+type NotificationChannelTwilio struct {
+	Type       NotificationChannelType `json:"type"`
+	ID         string                  `json:"id,omitempty"`
+	Name       string                  `json:"name"`
+	AccountSID string                  `json:"accountSid"`
+	AuthToken  string                  `json:"authToken"`
+	From       string                  `json:"from"`
+	To         string                  `json:"to"`
+	Events     []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeTwilio
+func (c *NotificationChannelTwilio) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeTwilio
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelTwilio) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelTwilio) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelTwilio) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelTwilio
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeTwilio
+	*c = NotificationChannelTwilio(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelTwilio) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelTwilio
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeTwilio
+	return json.Marshal(data)
+}
+
+// NotificationChannelPagerduty is a PagerDuty notification channel.
+type NotificationChannelPagerduty struct {
+	Type       NotificationChannelType `json:"type"`
+	ID         string                  `json:"id,omitempty"`
+	Name       string                  `json:"name"`
+	ServiceKey string                  `json:"serviceKey"`
+	Events     []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypePagerduty
+func (c *NotificationChannelPagerduty) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypePagerduty
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelPagerduty) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelPagerduty) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelPagerduty) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelPagerduty
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypePagerduty
+	*c = NotificationChannelPagerduty(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelPagerduty) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelPagerduty
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypePagerduty
+	return json.Marshal(data)
+}
+
+// NotificationChannelOpsgenie is an Opsgenie notification channel.
+type NotificationChannelOpsgenie struct {
+	Type   NotificationChannelType `json:"type"`
+	ID     string                  `json:"id,omitempty"`
+	Name   string                  `json:"name"`
+	APIKey string                  `json:"apiKey"`
+	Events []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeOpsgenie
+func (c *NotificationChannelOpsgenie) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeOpsgenie
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelOpsgenie) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelOpsgenie) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelOpsgenie) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelOpsgenie
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeOpsgenie
+	*c = NotificationChannelOpsgenie(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelOpsgenie) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelOpsgenie
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeOpsgenie
+	return json.Marshal(data)
+}
+
+// NotificationChannelMicrosoftTeams is a Microsoft Teams notification channel.
+type NotificationChannelMicrosoftTeams struct {
+	Type   NotificationChannelType `json:"type"`
+	ID     string                  `json:"id,omitempty"`
+	Name   string                  `json:"name"`
+	URL    string                  `json:"url"`
+	Events []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeMicrosoftTeams
+func (c *NotificationChannelMicrosoftTeams) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeMicrosoftTeams
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelMicrosoftTeams) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelMicrosoftTeams) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelMicrosoftTeams) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelMicrosoftTeams
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeMicrosoftTeams
+	*c = NotificationChannelMicrosoftTeams(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelMicrosoftTeams) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelMicrosoftTeams
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeMicrosoftTeams
+	return json.Marshal(data)
+}
+
+// NotificationChannelAmazonEventBridge is an Amazon EventBridge notification channel.
+type NotificationChannelAmazonEventBridge struct {
+	Type         NotificationChannelType `json:"type"`
+	ID           string                  `json:"id,omitempty"`
+	Name         string                  `json:"name"`
+	AWSAccountID string                  `json:"awsAccountId"`
+	Region       string                  `json:"region"`
+	Events       []NotificationEvent     `json:"events"`
+}
+
+// NotificationChannelType returns NotificationChannelTypeAmazonEventBridge
+func (c *NotificationChannelAmazonEventBridge) NotificationChannelType() NotificationChannelType {
+	return NotificationChannelTypeAmazonEventBridge
+}
+
+// NotificationChannelID returns the id of the channel.
+func (c *NotificationChannelAmazonEventBridge) NotificationChannelID() string {
+	return c.ID
+}
+
+// NotificationChannelName returns the name of the channel.
+func (c *NotificationChannelAmazonEventBridge) NotificationChannelName() string {
+	return c.Name
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelAmazonEventBridge) UnmarshalJSON(b []byte) error {
+	type channel NotificationChannelAmazonEventBridge
+	var data channel
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	data.Type = NotificationChannelTypeAmazonEventBridge
+	*c = NotificationChannelAmazonEventBridge(data)
+	return nil
+}
+
+// MarshalJSON implements json.Unmarshaler.
+func (c *NotificationChannelAmazonEventBridge) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannelAmazonEventBridge
+	data := *(*channel)(c)
+	data.Type = NotificationChannelTypeAmazonEventBridge
+	return json.Marshal(data)
+}
+
 type notificationChannel struct {
 	NotificationChannel
 }
@@ -295,6 +652,22 @@ func (c *notificationChannel) UnmarshalJSON(b []byte) error {
 		c.NotificationChannel = &NotificationChannelSlack{}
 	case NotificationChannelTypeWebHook:
 		c.NotificationChannel = &NotificationChannelWebHook{}
+	case NotificationChannelTypeLine:
+		c.NotificationChannel = &NotificationChannelLine{}
+	case NotificationChannelTypeChatwork:
+		c.NotificationChannel = &NotificationChannelChatwork{}
+	case NotificationChannelTypeTypetalk:
+		c.NotificationChannel = &NotificationChannelTypetalk{}
+	case NotificationChannelTypeTwilio:
+		c.NotificationChannel = &NotificationChannelTwilio{}
+	case NotificationChannelTypePagerduty:
+		c.NotificationChannel = &NotificationChannelPagerduty{}
+	case NotificationChannelTypeOpsgenie:
+		c.NotificationChannel = &NotificationChannelOpsgenie{}
+	case NotificationChannelTypeMicrosoftTeams:
+		c.NotificationChannel = &NotificationChannelMicrosoftTeams{}
+	case NotificationChannelTypeAmazonEventBridge:
+		c.NotificationChannel = &NotificationChannelAmazonEventBridge{}
 	default:
 		c.NotificationChannel = &NotificationChannelBase{}
 	}
@@ -331,7 +704,7 @@ func (c *Client) CreateNotificationChannel(ctx context.Context, ch NotificationC
 // DeleteNotificationChannel deletes a notification channel.
 func (c *Client) DeleteNotificationChannel(ctx context.Context, channelID string) (NotificationChannel, error) {
 	var ret notificationChannel
-	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v0/channels/%s", channelID), nil, &ret)
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v0/channels/%s", channelID), nil, &ret)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +97,208 @@ func TestFindNotificationChannels(t *testing.T) {
 			},
 		},
 
+		// line
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":     "ch-foobar",
+						"name":   "notification-test",
+						"type":   "line",
+						"token":  "line-notify-token",
+						"events": []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelLine{
+					ID:     "ch-foobar",
+					Name:   "notification-test",
+					Type:   NotificationChannelTypeLine,
+					Token:  "line-notify-token",
+					Events: []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// chatwork
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":     "ch-foobar",
+						"name":   "notification-test",
+						"type":   "chatwork",
+						"token":  "chatwork-api-token",
+						"roomId": "123456",
+						"events": []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelChatwork{
+					ID:     "ch-foobar",
+					Name:   "notification-test",
+					Type:   NotificationChannelTypeChatwork,
+					Token:  "chatwork-api-token",
+					RoomID: "123456",
+					Events: []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// typetalk
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":     "ch-foobar",
+						"name":   "notification-test",
+						"type":   "typetalk",
+						"url":    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+						"events": []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelTypetalk{
+					ID:     "ch-foobar",
+					Name:   "notification-test",
+					Type:   NotificationChannelTypeTypetalk,
+					URL:    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+					Events: []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// twilio
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":         "ch-foobar",
+						"name":       "notification-test",
+						"type":       "twilio",
+						"accountSid": "AC0123456789",
+						"authToken":  "twilio-auth-token",
+						"from":       "+815012345678",
+						"to":         "+819012345678",
+						"events":     []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelTwilio{
+					ID:         "ch-foobar",
+					Name:       "notification-test",
+					Type:       NotificationChannelTypeTwilio,
+					AccountSID: "AC0123456789",
+					AuthToken:  "twilio-auth-token",
+					From:       "+815012345678",
+					To:         "+819012345678",
+					Events:     []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// pagerduty
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":         "ch-foobar",
+						"name":       "notification-test",
+						"type":       "pagerduty",
+						"serviceKey": "pagerduty-integration-key",
+						"events":     []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelPagerduty{
+					ID:         "ch-foobar",
+					Name:       "notification-test",
+					Type:       NotificationChannelTypePagerduty,
+					ServiceKey: "pagerduty-integration-key",
+					Events:     []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// opsgenie
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":     "ch-foobar",
+						"name":   "notification-test",
+						"type":   "opsgenie",
+						"apiKey": "opsgenie-api-key",
+						"events": []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelOpsgenie{
+					ID:     "ch-foobar",
+					Name:   "notification-test",
+					Type:   NotificationChannelTypeOpsgenie,
+					APIKey: "opsgenie-api-key",
+					Events: []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// microsoft-teams
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":     "ch-foobar",
+						"name":   "notification-test",
+						"type":   "microsoft-teams",
+						"url":    "https://example.webhook.office.com/webhookb2/XXXXX",
+						"events": []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelMicrosoftTeams{
+					ID:     "ch-foobar",
+					Name:   "notification-test",
+					Type:   NotificationChannelTypeMicrosoftTeams,
+					URL:    "https://example.webhook.office.com/webhookb2/XXXXX",
+					Events: []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
+		// amazon-event-bridge
+		{
+			resp: map[string]any{
+				"channels": []any{
+					map[string]any{
+						"id":           "ch-foobar",
+						"name":         "notification-test",
+						"type":         "amazon-event-bridge",
+						"awsAccountId": "123456789012",
+						"region":       "ap-northeast-1",
+						"events":       []any{"alert"},
+					},
+				},
+			},
+			want: []NotificationChannel{
+				&NotificationChannelAmazonEventBridge{
+					ID:           "ch-foobar",
+					Name:         "notification-test",
+					Type:         NotificationChannelTypeAmazonEventBridge,
+					AWSAccountID: "123456789012",
+					Region:       "ap-northeast-1",
+					Events:       []NotificationEvent{NotificationEventAlert},
+				},
+			},
+		},
+
 		// other notification channel types
 		{
 			resp: map[string]any{
@@ -104,7 +306,7 @@ func TestFindNotificationChannels(t *testing.T) {
 					map[string]any{
 						"id":   "ch-foobar",
 						"name": "notification-test",
-						"type": "line",
+						"type": "hipchat",
 					},
 				},
 			},
@@ -112,7 +314,7 @@ func TestFindNotificationChannels(t *testing.T) {
 				&NotificationChannelBase{
 					ID:   "ch-foobar",
 					Name: "notification-test",
-					Type: NotificationChannelType("line"),
+					Type: NotificationChannelType("hipchat"),
 				},
 			},
 		},
@@ -213,6 +415,120 @@ func TestCreateNotificationChannel(t *testing.T) {
 				"events": []any{"alert"},
 			},
 		},
+		{
+			in: &NotificationChannelLine{
+				Name:   "notification-test",
+				Token:  "line-notify-token",
+				Events: []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":   "notification-test",
+				"type":   "line",
+				"token":  "line-notify-token",
+				"events": []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelChatwork{
+				Name:   "notification-test",
+				Token:  "chatwork-api-token",
+				RoomID: "123456",
+				Events: []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":   "notification-test",
+				"type":   "chatwork",
+				"token":  "chatwork-api-token",
+				"roomId": "123456",
+				"events": []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelTypetalk{
+				Name:   "notification-test",
+				URL:    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+				Events: []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":   "notification-test",
+				"type":   "typetalk",
+				"url":    "https://typetalk.com/api/v1/topics/12345?typetalkToken=XXXXX",
+				"events": []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelTwilio{
+				Name:       "notification-test",
+				AccountSID: "AC0123456789",
+				AuthToken:  "twilio-auth-token",
+				From:       "+815012345678",
+				To:         "+819012345678",
+				Events:     []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":       "notification-test",
+				"type":       "twilio",
+				"accountSid": "AC0123456789",
+				"authToken":  "twilio-auth-token",
+				"from":       "+815012345678",
+				"to":         "+819012345678",
+				"events":     []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelPagerduty{
+				Name:       "notification-test",
+				ServiceKey: "pagerduty-integration-key",
+				Events:     []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":       "notification-test",
+				"type":       "pagerduty",
+				"serviceKey": "pagerduty-integration-key",
+				"events":     []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelOpsgenie{
+				Name:   "notification-test",
+				APIKey: "opsgenie-api-key",
+				Events: []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":   "notification-test",
+				"type":   "opsgenie",
+				"apiKey": "opsgenie-api-key",
+				"events": []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelMicrosoftTeams{
+				Name:   "notification-test",
+				URL:    "https://example.webhook.office.com/webhookb2/XXXXX",
+				Events: []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":   "notification-test",
+				"type":   "microsoft-teams",
+				"url":    "https://example.webhook.office.com/webhookb2/XXXXX",
+				"events": []any{"alert"},
+			},
+		},
+		{
+			in: &NotificationChannelAmazonEventBridge{
+				Name:         "notification-test",
+				AWSAccountID: "123456789012",
+				Region:       "ap-northeast-1",
+				Events:       []NotificationEvent{NotificationEventAlert},
+			},
+			want: map[string]any{
+				"name":         "notification-test",
+				"type":         "amazon-event-bridge",
+				"awsAccountId": "123456789012",
+				"region":       "ap-northeast-1",
+				"events":       []any{"alert"},
+			},
+		},
 	}
 
 	for i, tc := range tests {
@@ -0,0 +1,104 @@
+package mackerel
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Client retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// The request is never retried if it is less than or equal to one.
+	MaxAttempts int
+
+	// BaseDelay is the initial delay of the exponential backoff.
+	BaseDelay time.Duration
+
+	// MaxDelay is the upper limit of the backoff delay.
+	// The delay requested by the Retry-After header is not limited by MaxDelay.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy used if Client.RetryPolicy is nil.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    20 * time.Second,
+}
+
+// backoff returns the delay before the next attempt.
+// attempt is the number of attempts that have already been made.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MaxDelay
+	if attempt < 32 {
+		if d := p.BaseDelay << (attempt - 1); d > 0 && d < delay {
+			delay = d
+		}
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// full jitter
+	return rand.N(delay + 1)
+}
+
+// isIdempotent reports whether the method is idempotent.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryable reports whether the request may be retried.
+// statusCode is zero if no response is received.
+func isRetryable(method string, statusCode int) bool {
+	// Mackerel rejects rate limited requests without processing them,
+	// so it is safe to retry them regardless of the method.
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(method) {
+		return false
+	}
+	switch statusCode {
+	case 0, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isAuthError reports whether Mackerel rejects the api key.
+func isAuthError(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+// parseRetryAfter parses the Retry-After header.
+// It returns zero if the header is missing or invalid.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	// delay-seconds
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if sec < 0 {
+			return 0
+		}
+		return time.Duration(sec) * time.Second
+	}
+
+	// HTTP-date
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
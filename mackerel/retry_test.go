@@ -0,0 +1,182 @@
+package mackerel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDo_Retry(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		header   map[string]string
+		policy   *RetryPolicy
+		deadline time.Duration
+		wantCall int32
+		wantErr  bool
+	}{
+		{
+			name:     "retry GET on server errors",
+			method:   http.MethodGet,
+			status:   http.StatusServiceUnavailable,
+			wantCall: 3,
+		},
+		{
+			name:     "retry POST on rate limit",
+			method:   http.MethodPost,
+			status:   http.StatusTooManyRequests,
+			wantCall: 3,
+		},
+		{
+			name:     "do not retry POST on server errors",
+			method:   http.MethodPost,
+			status:   http.StatusInternalServerError,
+			wantCall: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "do not retry on client errors",
+			method:   http.MethodPut,
+			status:   http.StatusBadRequest,
+			wantCall: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "give up after max attempts",
+			method:   http.MethodGet,
+			status:   http.StatusBadGateway,
+			policy:   &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			wantCall: 2,
+			wantErr:  true,
+		},
+		{
+			name:     "retry is disabled",
+			method:   http.MethodGet,
+			status:   http.StatusBadGateway,
+			policy:   &RetryPolicy{MaxAttempts: 1},
+			wantCall: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "give up if Retry-After exceeds the deadline",
+			method:   http.MethodGet,
+			status:   http.StatusTooManyRequests,
+			header:   map[string]string{"Retry-After": "60"},
+			deadline: 5 * time.Second,
+			wantCall: 1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var cnt int32
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tc.method {
+					t.Errorf("unexpected method: want %s, got %s", tc.method, r.Method)
+				}
+				if atomic.AddInt32(&cnt, 1) < 3 {
+					for k, v := range tc.header {
+						w.Header().Set(k, v)
+					}
+					w.WriteHeader(tc.status)
+					_, err := fmt.Fprintln(w, `{"error": {"message": "ERROR MESSAGE HERE"}}`)
+					if err != nil {
+						t.Error(err)
+					}
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				_, err := fmt.Fprintln(w, `{}`)
+				if err != nil {
+					t.Error(err)
+				}
+			}))
+			defer ts.Close()
+
+			u, err := url.Parse(ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			policy := tc.policy
+			if policy == nil {
+				policy = &RetryPolicy{
+					MaxAttempts: 5,
+					BaseDelay:   time.Millisecond,
+					MaxDelay:    10 * time.Millisecond,
+				}
+			}
+			c := &Client{
+				BaseURL:     u,
+				APIKey:      "DUMMY-API-KEY",
+				HTTPClient:  ts.Client(),
+				RetryPolicy: policy,
+			}
+
+			ctx := context.Background()
+			if tc.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.deadline)
+				defer cancel()
+			}
+			_, err = c.do(ctx, tc.method, "/foo/bar", map[string]any{}, nil)
+			if tc.wantErr {
+				merr, ok := err.(Error)
+				if !ok || merr.StatusCode() != tc.status {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err != nil {
+				t.Error(err)
+			}
+			if got := atomic.LoadInt32(&cnt); got != tc.wantCall {
+				t.Errorf("unexpected call count: want %d, got %d", tc.wantCall, got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2018, 3, 6, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"invalid", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0},
+	}
+
+	for _, tc := range tests {
+		h := http.Header{}
+		if tc.in != "" {
+			h.Set("Retry-After", tc.in)
+		}
+		if got := parseRetryAfter(h, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q): want %s, got %s", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{
+		MaxAttempts: 100,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+	for attempt := 1; attempt < 100; attempt++ {
+		got := p.backoff(attempt)
+		if got < 0 || got > p.MaxDelay {
+			t.Errorf("backoff(%d) = %s, want in [0, %s]", attempt, got, p.MaxDelay)
+		}
+	}
+}
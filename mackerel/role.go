@@ -18,6 +18,11 @@ type CreateRoleParam struct {
 	Memo string `json:"memo"`
 }
 
+// UpdateRoleParam parameters for UpdateRole.
+type UpdateRoleParam struct {
+	Memo string `json:"memo"`
+}
+
 // CreateRole creates a new role
 func (c *Client) CreateRole(ctx context.Context, serviceName string, param *CreateRoleParam) (*Role, error) {
 	role := &Role{}
@@ -28,6 +33,16 @@ func (c *Client) CreateRole(ctx context.Context, serviceName string, param *Crea
 	return role, nil
 }
 
+// UpdateRole updates the memo of a role
+func (c *Client) UpdateRole(ctx context.Context, serviceName, roleName string, param *UpdateRoleParam) (*Role, error) {
+	role := &Role{}
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v0/services/%s/roles/%s", serviceName, roleName), param, role)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
 // DeleteRole deletes a role
 func (c *Client) DeleteRole(ctx context.Context, serviceName, roleName string) (*Role, error) {
 	role := &Role{}
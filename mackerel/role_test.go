@@ -93,3 +93,45 @@ func TestDeleteRole(t *testing.T) {
 		t.Errorf("role differs: (-got +want)\n%s", diff)
 	}
 }
+
+func TestUpdateRole(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPut, r.Method)
+		}
+		if r.URL.Path != "/api/v0/services/awesome-service/roles/application" {
+			t.Errorf("unexpected path: want %s, got %s", "/api/v0/services/awesome-service/roles/application", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"name":"application","memo":"new memo"}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.UpdateRole(context.Background(), "awesome-service", "application", &UpdateRoleParam{
+		Memo: "new memo",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want := &Role{
+		Name: "application",
+		Memo: "new memo",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("role differs: (-got +want)\n%s", diff)
+	}
+}
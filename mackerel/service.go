@@ -19,6 +19,11 @@ type CreateServiceParam struct {
 	Memo string `json:"memo"`
 }
 
+// UpdateServiceParam parameters for UpdateService.
+type UpdateServiceParam struct {
+	Memo string `json:"memo"`
+}
+
 // FindServices return the list of services.
 func (c *Client) FindServices(ctx context.Context) ([]*Service, error) {
 	var resp struct {
@@ -41,6 +46,16 @@ func (c *Client) CreateService(ctx context.Context, param *CreateServiceParam) (
 	return service, nil
 }
 
+// UpdateService updates the memo of a service
+func (c *Client) UpdateService(ctx context.Context, serviceName string, param *UpdateServiceParam) (*Service, error) {
+	service := &Service{}
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v0/services/%s", serviceName), param, service)
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
 // DeleteService deletes a service
 func (c *Client) DeleteService(ctx context.Context, serviceName string) (*Service, error) {
 	service := &Service{}
@@ -49,3 +49,46 @@ func TestFindServices(t *testing.T) {
 		t.Errorf("metadata differs: (-got +want)\n%s", diff)
 	}
 }
+
+func TestUpdateService(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected method: want %s, got %s", http.MethodPut, r.Method)
+		}
+		if r.URL.Path != "/api/v0/services/awesome-service" {
+			t.Errorf("unexpected path: want %s, got %s", "/api/v0/services/awesome-service", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"name":"awesome-service","memo":"new memo","roles":["role1"]}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		BaseURL:    u,
+		APIKey:     "DUMMY-API-KEY",
+		HTTPClient: ts.Client(),
+	}
+
+	got, err := c.UpdateService(context.Background(), "awesome-service", &UpdateServiceParam{
+		Memo: "new memo",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want := &Service{
+		Name:  "awesome-service",
+		Memo:  "new memo",
+		Roles: []string{"role1"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("service differs: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,176 @@
+// Package macro implements the CloudFormation macro that transforms Mackerel resources into custom resources.
+// See https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/template-macros.html
+package macro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/cfn-mackerel-macro/spec"
+	"github.com/sirupsen/logrus"
+)
+
+// the prefix of the resource types that the macro transforms.
+const prefix = "Mackerel::"
+
+// Request is a request from CloudFormation to the macro.
+type Request struct {
+	Region                  string         `json:"region"`
+	AccountID               string         `json:"accountId"`
+	Fragment                map[string]any `json:"fragment"`
+	TransformID             string         `json:"transformId"`
+	Params                  map[string]any `json:"params"`
+	RequestID               string         `json:"requestId"`
+	TemplateParameterValues map[string]any `json:"templateParameterValues"`
+}
+
+// Response is a response from the macro to CloudFormation.
+type Response struct {
+	RequestID    string         `json:"requestId"`
+	Status       string         `json:"status"`
+	Fragment     map[string]any `json:"fragment"`
+	ErrorMessage string         `json:"errorMessage,omitempty"`
+}
+
+// Macro transforms Mackerel::* resources into Custom::* resources.
+type Macro struct {
+	// ServiceToken is the ARN of the function that handles the custom resources.
+	ServiceToken string
+
+	// Spec is the resource specification for validating the resources.
+	// If it is nil, the resources are not validated.
+	Spec *spec.Specification
+}
+
+// Handle handles the request from CloudFormation.
+// The errors are reported in the response, so CloudFormation shows them to the user.
+func (m *Macro) Handle(ctx context.Context, req *Request) (*Response, error) {
+	fragment, err := m.Transform(req.Fragment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"request_id": req.RequestID,
+			"error":      err,
+		}).Info("invalid template")
+		return &Response{
+			RequestID:    req.RequestID,
+			Status:       "failure",
+			Fragment:     req.Fragment,
+			ErrorMessage: errorMessage(err),
+		}, nil
+	}
+	return &Response{
+		RequestID: req.RequestID,
+		Status:    "success",
+		Fragment:  fragment,
+	}, nil
+}
+
+// Transform validates the Mackerel resources in the template, and transforms them into custom resources.
+// The template is modified in place.
+func (m *Macro) Transform(template map[string]any) (map[string]any, error) {
+	if err := m.validate(template); err != nil {
+		return nil, err
+	}
+
+	resources, _ := template["Resources"].(map[string]any)
+	for _, res := range resources {
+		r, ok := res.(map[string]any)
+		if !ok {
+			continue
+		}
+		typ, _ := r["Type"].(string)
+		if !strings.HasPrefix(typ, prefix) {
+			continue
+		}
+		properties, _ := r["Properties"].(map[string]any)
+		if properties == nil {
+			properties = map[string]any{}
+		}
+		properties["ServiceToken"] = m.ServiceToken
+		r["Type"] = "Custom::" + strings.TrimPrefix(typ, prefix)
+		r["Version"] = "1.0"
+		r["Properties"] = properties
+	}
+	return template, nil
+}
+
+// validate validates the Mackerel resources in the template.
+// The errors of other resources are ignored,
+// because they may refer to the resources that other macros generate.
+func (m *Macro) validate(template map[string]any) error {
+	resources, _ := template["Resources"].(map[string]any)
+	mackerelResources := map[string]bool{}
+	var errs []error
+	for _, logicalID := range sortedKeys(resources) {
+		r, ok := resources[logicalID].(map[string]any)
+		if !ok {
+			continue
+		}
+		typ, _ := r["Type"].(string)
+		if !strings.HasPrefix(typ, prefix) {
+			continue
+		}
+		mackerelResources[logicalID] = true
+		if _, ok := r["Properties"]; ok {
+			if _, ok := r["Properties"].(map[string]any); !ok {
+				errs = append(errs, &spec.ValidationError{
+					LogicalID: logicalID,
+					Message:   "Properties should be an object",
+				})
+				continue
+			}
+		}
+		if m.Spec != nil {
+			if _, ok := m.Spec.ResourceTypes[typ]; !ok {
+				errs = append(errs, &spec.ValidationError{
+					LogicalID: logicalID,
+					Message:   fmt.Sprintf("unknown resource type: %s", typ),
+				})
+			}
+		}
+	}
+	if m.Spec == nil {
+		return errors.Join(errs...)
+	}
+
+	if err := m.Spec.ValidateTemplate(template); err != nil {
+		for _, err := range unwrapJoined(err) {
+			var verr *spec.ValidationError
+			if errors.As(err, &verr) && !mackerelResources[verr.LogicalID] {
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// errorMessage formats the error in a line, because CloudFormation shows the message in a line.
+func errorMessage(err error) string {
+	errs := unwrapJoined(err)
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "invalid Mackerel resources: " + strings.Join(msgs, "; ")
+}
+
+// unwrapJoined returns the errors joined by errors.Join.
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package macro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shogo82148/cfn-mackerel-macro/spec"
+)
+
+const serviceToken = "arn:aws:lambda:ap-northeast-1:123456789012:function:cfn-mackerel-macro"
+
+func newMacro(t *testing.T) *Macro {
+	t.Helper()
+	s, err := spec.Load("../cfn-resource-specification.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Macro{
+		ServiceToken: serviceToken,
+		Spec:         s,
+	}
+}
+
+func TestHandle(t *testing.T) {
+	m := newMacro(t)
+	resp, err := m.Handle(context.Background(), &Request{
+		RequestID: "request-id",
+		Fragment: map[string]any{
+			"Resources": map[string]any{
+				"Org": map[string]any{
+					"Type": "Mackerel::Org",
+				},
+				"Service": map[string]any{
+					"Type": "Mackerel::Service",
+					"Properties": map[string]any{
+						"Name": "service",
+					},
+				},
+				"Topic": map[string]any{
+					"Type": "AWS::SNS::Topic",
+					"Properties": map[string]any{
+						"DisplayName": map[string]any{"Fn::GetAtt": []any{"Service", "Name"}},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Response{
+		RequestID: "request-id",
+		Status:    "success",
+		Fragment: map[string]any{
+			"Resources": map[string]any{
+				"Org": map[string]any{
+					"Type":    "Custom::Org",
+					"Version": "1.0",
+					"Properties": map[string]any{
+						"ServiceToken": serviceToken,
+					},
+				},
+				"Service": map[string]any{
+					"Type":    "Custom::Service",
+					"Version": "1.0",
+					"Properties": map[string]any{
+						"Name":         "service",
+						"ServiceToken": serviceToken,
+					},
+				},
+				"Topic": map[string]any{
+					"Type": "AWS::SNS::Topic",
+					"Properties": map[string]any{
+						"DisplayName": map[string]any{"Fn::GetAtt": []any{"Service", "Name"}},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(resp, want); diff != "" {
+		t.Errorf("response differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestHandle_invalid(t *testing.T) {
+	m := newMacro(t)
+	fragment := map[string]any{
+		"Resources": map[string]any{
+			"Monitor": map[string]any{
+				"Type": "Mackerel::Monitor",
+				"Properties": map[string]any{
+					"Type":                 "connectivity",
+					"Name":                 "connectivity",
+					"NotificationInterval": "ten minutes",
+					"Scopes":               []any{map[string]any{"Ref": "Role"}},
+				},
+			},
+			"Service": map[string]any{
+				"Type": "Mackerel::Service",
+				"Properties": map[string]any{
+					"Nmae": "service",
+				},
+			},
+			"Unknown": map[string]any{
+				"Type": "Mackerel::Unknown",
+			},
+
+			// the other resources are not validated.
+			"Function": map[string]any{
+				"Type": "AWS::Lambda::Function",
+				"Properties": map[string]any{
+					"Role": map[string]any{"Fn::GetAtt": []any{"FunctionRole", "Arn"}},
+				},
+			},
+		},
+	}
+	resp, err := m.Handle(context.Background(), &Request{
+		RequestID: "request-id",
+		Fragment:  fragment,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Response{
+		RequestID: "request-id",
+		Status:    "failure",
+		Fragment:  fragment,
+		ErrorMessage: "invalid Mackerel resources: " +
+			"Unknown: unknown resource type: Mackerel::Unknown; " +
+			"Monitor: Scopes[0]: unresolved reference: Role; " +
+			"Monitor: NotificationInterval: should be Integer, but got a string; " +
+			"Service: required property Name is missing; " +
+			"Service: Nmae: unknown property",
+	}
+	if diff := cmp.Diff(resp, want); diff != "" {
+		t.Errorf("response differs: (-got +want)\n%s", diff)
+	}
+}
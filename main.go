@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	_ "embed"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/shogo82148/cfn-mackerel-macro/cfn"
 	"github.com/shogo82148/cfn-mackerel-macro/mackerel/apikey/aws"
+	"github.com/shogo82148/cfn-mackerel-macro/macro"
+	"github.com/shogo82148/cfn-mackerel-macro/spec"
 	"github.com/sirupsen/logrus"
 )
 
+//go:embed cfn-resource-specification.json
+var specification []byte
+
 func init() {
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 
@@ -31,11 +38,23 @@ func init() {
 func main() {
 	logrus.Infof("cfn_mackerel_macro v%s", version)
 
+	// the same binary serves both the macro and the custom resources.
+	// the runtime tells which one is invoked by the handler name.
+	if os.Getenv("_HANDLER") == "macro" {
+		startMacro()
+		return
+	}
+
 	provider, err := aws.LoadDefaultProvider(context.Background())
 	if err != nil {
 		logrus.WithError(err).Error("fail to load aws config")
 		os.Exit(1)
 	}
+	resolver, err := aws.LoadDefaultResolver(context.Background())
+	if err != nil {
+		logrus.WithError(err).Error("fail to load aws config")
+		os.Exit(1)
+	}
 
 	var u *url.URL
 	if base := os.Getenv("MACKEREL_APIURL"); base != "" {
@@ -47,10 +66,36 @@ func main() {
 		}
 	}
 
+	var ttl time.Duration
+	if s := os.Getenv("MACKEREL_APIKEY_TTL"); s != "" {
+		var err error
+		ttl, err = time.ParseDuration(s)
+		if err != nil {
+			logrus.WithError(err).Error("fail to parse the ttl of api key")
+			os.Exit(1)
+		}
+	}
+
 	f := cfn.Function{
 		APIKeyProvider: provider,
+		APIKeyTTL:      ttl,
+		APIKeyResolver: resolver,
 		BaseURL:        u,
 		Version:        version,
 	}
 	lambda.Start(f.LambdaWrap())
 }
+
+func startMacro() {
+	s, err := spec.Parse(specification)
+	if err != nil {
+		logrus.WithError(err).Error("fail to parse the resource specification")
+		os.Exit(1)
+	}
+
+	m := &macro.Macro{
+		ServiceToken: os.Getenv("LAMBDA_ARN"),
+		Spec:         s,
+	}
+	lambda.Start(m.Handle)
+}
@@ -13,7 +13,6 @@ DIST=$ROOT/.build/$VERSION
 mkdir -p "$DIST"
 
 make all
-cp macro.zip "$DIST"
 cp resource.zip "$DIST"
 cp template.yaml "$DIST"
 cp README.md "$DIST"
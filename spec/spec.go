@@ -0,0 +1,396 @@
+// Package spec validates templates against the resource specification of CloudFormation.
+// See https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/cfn-resource-specification-format.html
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Specification is a resource specification.
+type Specification struct {
+	ResourceSpecificationVersion string                          `json:"ResourceSpecificationVersion"`
+	PropertyTypes                map[string]map[string]*Property `json:"PropertyTypes"`
+	ResourceTypes                map[string]*ResourceType        `json:"ResourceTypes"`
+}
+
+// ResourceType is a specification of a resource type.
+type ResourceType struct {
+	Documentation string                `json:"Documentation,omitempty"`
+	Attributes    map[string]*Attribute `json:"Attributes,omitempty"`
+	Properties    map[string]*Property  `json:"Properties,omitempty"`
+}
+
+// Property is a specification of a property.
+type Property struct {
+	Documentation     string `json:"Documentation,omitempty"`
+	DuplicatesAllowed bool   `json:"DuplicatesAllowed,omitempty"`
+	ItemType          string `json:"ItemType,omitempty"`
+	PrimitiveItemType string `json:"PrimitiveItemType,omitempty"`
+	PrimitiveType     string `json:"PrimitiveType,omitempty"`
+	Required          bool   `json:"Required,omitempty"`
+	Type              string `json:"Type,omitempty"`
+	UpdateType        string `json:"UpdateType,omitempty"`
+}
+
+// Attribute is a specification of an attribute for Fn::GetAtt.
+type Attribute struct {
+	ItemType          string `json:"ItemType,omitempty"`
+	PrimitiveItemType string `json:"PrimitiveItemType,omitempty"`
+	PrimitiveType     string `json:"PrimitiveType,omitempty"`
+	Type              string `json:"Type,omitempty"`
+}
+
+// Parse parses a resource specification.
+func Parse(data []byte) (*Specification, error) {
+	var s Specification
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Load loads a resource specification from the file.
+func Load(name string) (*Specification, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// ValidationError is an error of a property.
+type ValidationError struct {
+	// LogicalID is the logical id of the resource.
+	// It is empty if the error is returned by ValidateResource.
+	LogicalID string
+
+	// Path is the path to the property, e.g. Widgets[0].Layout.Width.
+	// It is empty if the error is about the resource itself.
+	Path string
+
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	var buf strings.Builder
+	if e.LogicalID != "" {
+		buf.WriteString(e.LogicalID)
+		buf.WriteString(": ")
+	}
+	if e.Path != "" {
+		buf.WriteString(e.Path)
+		buf.WriteString(": ")
+	}
+	buf.WriteString(e.Message)
+	return buf.String()
+}
+
+// ValidateResource validates the properties of the resource.
+// The values of intrinsic functions, e.g. {"Ref": "Foo"}, are not validated.
+// It returns the errors joined by errors.Join, and each of them is a *ValidationError.
+func (s *Specification) ValidateResource(typ string, properties map[string]any) error {
+	v := &validator{
+		spec: s,
+	}
+	v.validateResource(typ, properties)
+	return errors.Join(v.errs...)
+}
+
+// ValidateTemplate validates the resources in the template.
+// The resources whose types are not defined in the specification are skipped.
+// In addition to the properties, it checks the targets of Ref and Fn::GetAtt.
+// It returns the errors joined by errors.Join, and each of them is a *ValidationError.
+func (s *Specification) ValidateTemplate(template map[string]any) error {
+	parameters, _ := template["Parameters"].(map[string]any)
+	resources, _ := template["Resources"].(map[string]any)
+	types := make(map[string]string, len(resources))
+	for logicalID, res := range resources {
+		r, _ := res.(map[string]any)
+		typ, _ := r["Type"].(string)
+		types[logicalID] = typ
+	}
+
+	var errs []error
+	for _, logicalID := range sortedKeys(resources) {
+		r, _ := resources[logicalID].(map[string]any)
+		properties, _ := r["Properties"].(map[string]any)
+		v := &validator{
+			spec:       s,
+			logicalID:  logicalID,
+			parameters: parameters,
+			types:      types,
+		}
+		v.checkReferences("", properties)
+		if _, ok := s.ResourceTypes[types[logicalID]]; ok {
+			v.validateResource(types[logicalID], properties)
+		}
+		errs = append(errs, v.errs...)
+	}
+	return errors.Join(errs...)
+}
+
+type validator struct {
+	spec       *Specification
+	typePrefix string
+	logicalID  string
+	errs       []error
+
+	// for checking references
+	parameters map[string]any
+	types      map[string]string
+}
+
+func (v *validator) addError(path, format string, a ...any) {
+	v.errs = append(v.errs, &ValidationError{
+		LogicalID: v.logicalID,
+		Path:      path,
+		Message:   fmt.Sprintf(format, a...),
+	})
+}
+
+func (v *validator) validateResource(typ string, properties map[string]any) {
+	rt, ok := v.spec.ResourceTypes[typ]
+	if !ok {
+		v.addError("", "unknown resource type: %s", typ)
+		return
+	}
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	v.typePrefix = typ
+	v.validateProperties("", rt.Properties, properties)
+}
+
+// checkReferences checks that the targets of Ref and Fn::GetAtt exist.
+func (v *validator) checkReferences(path string, value any) {
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := value["Ref"].(string); ok && len(value) == 1 {
+			if _, ok := v.parameters[ref]; ok {
+				return
+			}
+			if _, ok := v.types[ref]; ok {
+				return
+			}
+			if strings.HasPrefix(ref, "AWS::") {
+				return
+			}
+			v.addError(path, "unresolved reference: %s", ref)
+			return
+		}
+		if args, ok := value["Fn::GetAtt"].([]any); ok && len(value) == 1 {
+			if len(args) != 2 {
+				v.addError(path, "Fn::GetAtt requires a logical id and an attribute name")
+				return
+			}
+			logicalID, _ := args[0].(string)
+			attr, _ := args[1].(string)
+			typ, ok := v.types[logicalID]
+			if !ok {
+				v.addError(path, "unresolved reference: %s", logicalID)
+				return
+			}
+			if rt, ok := v.spec.ResourceTypes[typ]; ok {
+				if _, ok := rt.Attributes[attr]; !ok {
+					v.addError(path, "%s does not have the attribute %s", typ, attr)
+				}
+			}
+			return
+		}
+		for _, key := range sortedKeys(value) {
+			v.checkReferences(joinPath(path, key), value[key])
+		}
+	case []any:
+		for i, item := range value {
+			v.checkReferences(fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	}
+}
+
+func (v *validator) validateProperties(path string, props map[string]*Property, value any) {
+	if IsIntrinsicFunction(value) {
+		return
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		v.addError(path, "should be an object, but got %s", typeName(value))
+		return
+	}
+
+	for _, name := range sortedKeys(props) {
+		if _, ok := m[name]; !ok && props[name].Required {
+			v.addError(path, "required property %s is missing", name)
+		}
+	}
+
+	for _, key := range sortedKeys(m) {
+		p := joinPath(path, key)
+		prop, ok := props[key]
+		if !ok {
+			v.addError(p, "unknown property")
+			continue
+		}
+		v.validateProperty(p, prop, m[key])
+	}
+}
+
+func (v *validator) validateProperty(path string, prop *Property, value any) {
+	if IsIntrinsicFunction(value) {
+		return
+	}
+	if prop.PrimitiveType != "" {
+		v.validatePrimitive(path, prop.PrimitiveType, value)
+		return
+	}
+	switch prop.Type {
+	case "List":
+		list, ok := value.([]any)
+		if !ok {
+			v.addError(path, "should be a list, but got %s", typeName(value))
+			return
+		}
+		for i, item := range list {
+			v.validateItem(fmt.Sprintf("%s[%d]", path, i), prop, item)
+		}
+	case "Map":
+		m, ok := value.(map[string]any)
+		if !ok {
+			v.addError(path, "should be a map, but got %s", typeName(value))
+			return
+		}
+		for _, key := range sortedKeys(m) {
+			v.validateItem(joinPath(path, key), prop, m[key])
+		}
+	default:
+		v.validatePropertyType(path, prop.Type, value)
+	}
+}
+
+func (v *validator) validateItem(path string, prop *Property, value any) {
+	if IsIntrinsicFunction(value) {
+		return
+	}
+	if prop.PrimitiveItemType != "" {
+		v.validatePrimitive(path, prop.PrimitiveItemType, value)
+		return
+	}
+	v.validatePropertyType(path, prop.ItemType, value)
+}
+
+func (v *validator) validatePropertyType(path, name string, value any) {
+	props, ok := v.spec.PropertyTypes[v.typePrefix+"."+name]
+	if !ok {
+		v.addError(path, "unknown property type: %s", name)
+		return
+	}
+	v.validateProperties(path, props, value)
+}
+
+func (v *validator) validatePrimitive(path, typ string, value any) {
+	switch typ {
+	case "String", "Timestamp":
+		switch value.(type) {
+		case string, float64, int, int64, bool:
+			// CloudFormation converts the numbers and the booleans into strings.
+			return
+		}
+	case "Integer", "Long":
+		switch value := value.(type) {
+		case int, int64:
+			return
+		case float64:
+			if value == math.Trunc(value) {
+				return
+			}
+		case string:
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return
+			}
+		}
+	case "Double":
+		switch value := value.(type) {
+		case int, int64, float64:
+			return
+		case string:
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				return
+			}
+		}
+	case "Boolean":
+		switch value := value.(type) {
+		case bool:
+			return
+		case string:
+			if value == "true" || value == "false" {
+				return
+			}
+		}
+	case "Json":
+		switch value.(type) {
+		case map[string]any, []any, string:
+			return
+		}
+	default:
+		v.addError(path, "unknown primitive type: %s", typ)
+		return
+	}
+	v.addError(path, "should be %s, but got %s", typ, typeName(value))
+}
+
+// IsIntrinsicFunction reports whether v is an intrinsic function, e.g. {"Ref": "Foo"} or {"Fn::GetAtt": ["Foo", "Bar"]}.
+func IsIntrinsicFunction(v any) bool {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return false
+	}
+	for key := range m {
+		return key == "Ref" || key == "Condition" || strings.HasPrefix(key, "Fn::")
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case float64, int, int64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
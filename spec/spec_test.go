@@ -0,0 +1,137 @@
+package spec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func loadSpec(t *testing.T) *Specification {
+	t.Helper()
+	s, err := Load("../cfn-resource-specification.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestValidateResource(t *testing.T) {
+	s := loadSpec(t)
+	err := s.ValidateResource("Mackerel::Dashboard", map[string]any{
+		"Title":   "dashboard",
+		"UrlPath": map[string]any{"Fn::Sub": "${AWS::StackName}"},
+		"Widgets": []any{
+			map[string]any{
+				"Type":  "graph",
+				"Title": "graph",
+				"Graph": map[string]any{
+					"Type":      "role",
+					"Role":      map[string]any{"Ref": "Role"},
+					"Name":      "loadavg5",
+					"IsStacked": "true",
+				},
+				"Layout": map[string]any{
+					"X": float64(0), "Y": "0", "Width": 8, "Height": int64(6),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateResource_invalid(t *testing.T) {
+	s := loadSpec(t)
+	err := s.ValidateResource("Mackerel::Dashboard", map[string]any{
+		"Title": "dashboard",
+		"Widgets": []any{
+			map[string]any{
+				"Type":  "graph",
+				"Title": "graph",
+				"Graph": map[string]any{
+					"Type":  "role",
+					"Stack": true,
+				},
+				"Layout": map[string]any{
+					"X": 0, "Y": 0, "Width": 8.5, "Height": []any{},
+				},
+			},
+		},
+	})
+	want := []string{
+		"required property UrlPath is missing",
+		"Widgets[0].Graph.Stack: unknown property",
+		"Widgets[0].Layout.Height: should be Integer, but got a list",
+		"Widgets[0].Layout.Width: should be Integer, but got a number",
+	}
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	got := strings.Split(err.Error(), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected errors:\ngot:\n%s\nwant:\n%s", err, strings.Join(want, "\n"))
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("want *ValidationError, got %T", err)
+	}
+}
+
+func TestValidateResource_unknownType(t *testing.T) {
+	s := loadSpec(t)
+	err := s.ValidateResource("Mackerel::Unknown", map[string]any{})
+	if err == nil || err.Error() != "unknown resource type: Mackerel::Unknown" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	s := loadSpec(t)
+	err := s.ValidateTemplate(map[string]any{
+		"Parameters": map[string]any{
+			"Env": map[string]any{"Type": "String"},
+		},
+		"Resources": map[string]any{
+			"Service": map[string]any{
+				"Type": "Mackerel::Service",
+				"Properties": map[string]any{
+					"Name": map[string]any{"Fn::Sub": "service-${Env}"},
+				},
+			},
+			"Role": map[string]any{
+				"Type": "Mackerel::Role",
+				"Properties": map[string]any{
+					"Service": map[string]any{"Ref": "Service"},
+					"Name":    map[string]any{"Ref": "Env"},
+				},
+			},
+			"Monitor": map[string]any{
+				"Type": "Mackerel::Monitor",
+				"Properties": map[string]any{
+					"Type":   "connectivity",
+					"Name":   map[string]any{"Fn::GetAtt": []any{"Role", "Unknown"}},
+					"Scopes": []any{map[string]any{"Ref": "Unknown"}},
+					"Memo":   map[string]any{"Ref": "AWS::StackName"},
+				},
+			},
+			"Topic": map[string]any{
+				"Type": "AWS::SNS::Topic",
+				"Properties": map[string]any{
+					"Unknown": true,
+				},
+			},
+		},
+	})
+	want := []string{
+		"Monitor: Name: Mackerel::Role does not have the attribute Unknown",
+		"Monitor: Scopes[0]: unresolved reference: Unknown",
+	}
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if err.Error() != strings.Join(want, "\n") {
+		t.Errorf("unexpected errors:\ngot:\n%s\nwant:\n%s", err, strings.Join(want, "\n"))
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -42,3 +44,58 @@ func TestSpecification(t *testing.T) {
 		}
 	}
 }
+
+func TestSpecificationProperties(t *testing.T) {
+	type property struct {
+		ItemType          string
+		PrimitiveItemType string
+		PrimitiveType     string
+		Type              string
+	}
+	var spec struct {
+		PropertyTypes map[string]map[string]property
+		ResourceTypes map[string]struct {
+			Properties map[string]property
+		}
+	}
+	data, err := os.ReadFile("cfn-resource-specification.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	types := map[string]map[string]property{}
+	for name, props := range spec.PropertyTypes {
+		types[name] = props
+	}
+	for name, typ := range spec.ResourceTypes {
+		types[name] = typ.Properties
+	}
+
+	// the lists must have the type of their items.
+	for name, props := range types {
+		for key, prop := range props {
+			if prop.Type != "List" {
+				continue
+			}
+			if prop.PrimitiveType != "" || (prop.ItemType == "" && prop.PrimitiveItemType == "") {
+				t.Errorf("%s.%s: invalid type of the list items", name, key)
+			}
+		}
+	}
+
+	// the properties that the resource handlers read.
+	tests := []struct {
+		typ  string
+		name string
+	}{
+		{"Mackerel::AWSIntegration.Tag", "Key"},
+		{"Mackerel::Dashboard.Graph", "IsStacked"},
+	}
+	for _, tt := range tests {
+		if _, ok := types[tt.typ][tt.name]; !ok {
+			t.Errorf("%s.%s is not found", tt.typ, tt.name)
+		}
+	}
+}